@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// logExt file extension employed on container log files.
+const logExt = ".log"
+
+// Archive stores BuildRun container logs on the local file system, organized by namespace,
+// BuildRun and Pod names, so the logs remain available after the build pod is gone.
+type Archive struct {
+	dir string // base directory
+}
+
+// Container represents a archived container log file.
+type Container struct {
+	Pod  string // pod name
+	Name string // container name
+	Path string // log file path
+}
+
+// Dir exposes the base directory.
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// buildRunDir returns the directory storing the logs of the informed BuildRun.
+func (a *Archive) buildRunDir(ns, buildRun string) string {
+	return filepath.Join(a.dir, ns, buildRun)
+}
+
+// Create creates, or truncates, the log file for the informed container.
+func (a *Archive) Create(ns, buildRun, pod, container string) (io.WriteCloser, error) {
+	dir := filepath.Join(a.buildRunDir(ns, buildRun), pod)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, container+logExt))
+}
+
+// Containers lists the archived container logs of the informed BuildRun, sorted by pod and
+// container names. An empty list is returned when nothing is archived.
+func (a *Archive) Containers(ns, buildRun string) ([]Container, error) {
+	matches, err := filepath.Glob(filepath.Join(a.buildRunDir(ns, buildRun), "*", "*"+logExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	containers := []Container{}
+	for _, match := range matches {
+		containers = append(containers, Container{
+			Pod:  filepath.Base(filepath.Dir(match)),
+			Name: strings.TrimSuffix(filepath.Base(match), logExt),
+			Path: match,
+		})
+	}
+	return containers, nil
+}
+
+// NewArchive instantiate the Archive on the informed base directory.
+func NewArchive(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// NewDefaultArchive instantiate the Archive on the user cache directory.
+func NewDefaultArchive() (*Archive, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the user cache directory: %w", err)
+	}
+	return NewArchive(filepath.Join(cacheDir, "shp", "logs")), nil
+}
+
+// Targets returns the archives the logs should be written to, the default archive when enabled
+// and the informed "save-to" directory, when not empty.
+func Targets(enabled bool, saveTo string) ([]*Archive, error) {
+	archives := []*Archive{}
+	if enabled {
+		a, err := NewDefaultArchive()
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, a)
+	}
+	if saveTo != "" {
+		archives = append(archives, NewArchive(saveTo))
+	}
+	return archives, nil
+}
@@ -0,0 +1,44 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Archive(t *testing.T) {
+	g := NewWithT(t)
+
+	a := NewArchive(t.TempDir())
+
+	containers, err := a.Containers("default", "buildrun")
+	g.Expect(err).To(BeNil())
+	g.Expect(containers).To(BeEmpty())
+
+	for _, container := range []string{"step-build", "step-source-default"} {
+		w, err := a.Create("default", "buildrun", "pod", container)
+		g.Expect(err).To(BeNil())
+		_, err = w.Write([]byte("line\n"))
+		g.Expect(err).To(BeNil())
+		g.Expect(w.Close()).To(Succeed())
+	}
+
+	containers, err = a.Containers("default", "buildrun")
+	g.Expect(err).To(BeNil())
+	g.Expect(containers).To(HaveLen(2))
+	g.Expect(containers[0].Pod).To(Equal("pod"))
+	g.Expect(containers[0].Name).To(Equal("step-build"))
+	g.Expect(containers[1].Name).To(Equal("step-source-default"))
+
+	data, err := os.ReadFile(containers[0].Path)
+	g.Expect(err).To(BeNil())
+	g.Expect(string(data)).To(Equal("line\n"))
+	g.Expect(containers[0].Path).To(Equal(filepath.Join(a.Dir(), "default", "buildrun", "pod", "step-build.log")))
+
+	// other buildruns are not affected
+	containers, err = a.Containers("default", "other")
+	g.Expect(err).To(BeNil())
+	g.Expect(containers).To(BeEmpty())
+}
@@ -0,0 +1,78 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// dockerConfigKeychain resolves the registry credentials from a Docker configuration file, the
+// same format of "~/.docker/config.json" and "kubernetes.io/dockerconfigjson" secrets.
+type dockerConfigKeychain struct {
+	cf *configfile.ConfigFile
+}
+
+// Resolve implements authn.Keychain, looking up the credentials by repository and by registry,
+// Docker Hub credentials are stored on a historical key.
+func (k *dockerConfigKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	var empty types.AuthConfig
+	for _, key := range []string{target.String(), target.RegistryStr()} {
+		if key == name.DefaultRegistry {
+			key = authn.DefaultAuthKey
+		}
+		cfg, err := k.cf.GetAuthConfig(key)
+		if err != nil {
+			return nil, err
+		}
+		if cfg != empty {
+			return authn.FromConfig(authn.AuthConfig{
+				Username:      cfg.Username,
+				Password:      cfg.Password,
+				Auth:          cfg.Auth,
+				IdentityToken: cfg.IdentityToken,
+				RegistryToken: cfg.RegistryToken,
+			}), nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// NewDockerConfigKeychain instantiates a keychain from the Docker configuration informed.
+func NewDockerConfigKeychain(r io.Reader) (authn.Keychain, error) {
+	cf, err := config.LoadFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse registry configuration: %w", err)
+	}
+	return &dockerConfigKeychain{cf: cf}, nil
+}
+
+// NewDockerConfigFileKeychain instantiates a keychain from the Docker configuration file informed.
+func NewDockerConfigFileKeychain(fpath string) (authn.Keychain, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewDockerConfigKeychain(f)
+}
+
+// basicKeychain resolves the same username and password for any registry.
+type basicKeychain struct {
+	auth authn.Authenticator
+}
+
+// Resolve implements authn.Keychain.
+func (k *basicKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.auth, nil
+}
+
+// NewBasicKeychain instantiates a keychain with the username and password informed.
+func NewBasicKeychain(username, password string) authn.Keychain {
+	return &basicKeychain{auth: &authn.Basic{Username: username, Password: password}}
+}
@@ -0,0 +1,67 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const dockerConfig = `{
+	"auths": {
+		"registry.local:5000": {"username": "local", "password": "secret"},
+		"https://index.docker.io/v1/": {"auth": "aHViOnRva2Vu"}
+	}
+}`
+
+// resolve returns the authentication resolved by the keychain for the informed image.
+func resolve(g *WithT, keychain authn.Keychain, image string) *authn.AuthConfig {
+	ref, err := name.NewTag(image)
+	g.Expect(err).To(BeNil())
+	auth, err := keychain.Resolve(ref.Context())
+	g.Expect(err).To(BeNil())
+	cfg, err := auth.Authorization()
+	g.Expect(err).To(BeNil())
+	return cfg
+}
+
+func TestDockerConfigKeychain(t *testing.T) {
+	g := NewWithT(t)
+
+	keychain, err := NewDockerConfigKeychain(strings.NewReader(dockerConfig))
+	g.Expect(err).To(BeNil())
+
+	cfg := resolve(g, keychain, "registry.local:5000/source/bundle:latest")
+	g.Expect(cfg.Username).To(Equal("local"))
+	g.Expect(cfg.Password).To(Equal("secret"))
+
+	// docker hub credentials are stored on the historical key
+	cfg = resolve(g, keychain, "shipwright/bundle:latest")
+	g.Expect(cfg.Username).To(Equal("hub"))
+	g.Expect(cfg.Password).To(Equal("token"))
+
+	cfg = resolve(g, keychain, "quay.io/shipwright/bundle:latest")
+	g.Expect(*cfg).To(Equal(authn.AuthConfig{}))
+
+	fpath := filepath.Join(t.TempDir(), "config.json")
+	g.Expect(os.WriteFile(fpath, []byte(dockerConfig), 0o600)).To(Succeed())
+	keychain, err = NewDockerConfigFileKeychain(fpath)
+	g.Expect(err).To(BeNil())
+	g.Expect(resolve(g, keychain, "registry.local:5000/bundle").Username).To(Equal("local"))
+
+	_, err = NewDockerConfigKeychain(strings.NewReader("{"))
+	g.Expect(err).To(MatchError(ContainSubstring("unable to parse registry configuration")))
+}
+
+func TestBasicKeychain(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := resolve(g, NewBasicKeychain("user", "pass"), "registry.local/bundle")
+	g.Expect(cfg.Username).To(Equal("user"))
+	g.Expect(cfg.Password).To(Equal("pass"))
+}
@@ -3,28 +3,40 @@ package bundle
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	progressbar "github.com/schollz/progressbar/v3"
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
-	buildbundle "github.com/shipwright-io/build/pkg/bundle"
 	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
 )
 
 // GetSourceBundleImage returns the source bundle image of the build that is
-// associated with the provided buildrun, an empty string if source bundle is
-// not used, or an error in case the build cannot be obtained
+// associated with the provided buildrun, or embedded on it, an empty string if
+// source bundle is not used, or an error in case the build cannot be obtained
 func GetSourceBundleImage(ctx context.Context, client buildclientset.Interface, buildRun *buildv1alpha1.BuildRun) (string, error) {
 	if buildRun == nil {
 		return "", fmt.Errorf("no buildrun provided, given reference is nil")
 	}
 
+	if buildRun.Spec.BuildSpec != nil {
+		if bundle := buildRun.Spec.BuildSpec.Source.BundleContainer; bundle != nil && bundle.Image != "" {
+			return bundle.Image, nil
+		}
+	}
+
 	if buildRun.Spec.BuildRef != nil {
 		name, namespace := buildRun.Spec.BuildRef.Name, buildRun.Namespace
 
@@ -41,21 +53,94 @@ func GetSourceBundleImage(ctx context.Context, client buildclientset.Interface,
 	return "", nil
 }
 
+// pack creates the bundle image with a single layer holding the tarball of the
+// local directory, the tarball is reproducible and the creation time is fixed
+// to produce the same digest for the same contents. The bundle unpacking only
+// supports directories and regular files, so symbolic links are replaced by
+// the files they point to. The tarball is spooled on a file in the informed
+// directory, read every time the layer is, thus the directory must be kept
+// while the image is in use. The informed tarball is not modified, the bundle
+// settings are applied on a copy.
+func pack(src *streamer.Tar, dir string) (v1.Image, error) {
+	bundleTar := *src
+	bundleTar.WithDereferenceSymlinks().WithReproducible()
+
+	spool, err := os.CreateTemp(dir, "bundle-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	if err = bundleTar.Create(spool); err != nil {
+		spool.Close()
+		return nil, err
+	}
+	if err = spool.Close(); err != nil {
+		return nil, err
+	}
+
+	layer, err := tarball.LayerFromFile(spool.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return nil, err
+	}
+	return mutate.Time(image, time.Unix(0, 0))
+}
+
+// Options registry settings employed to push and pull the bundle.
+type Options struct {
+	Keychain authn.Keychain // registry credentials, the default keychain when not informed
+	Insecure bool           // allows plain HTTP registries
+}
+
+// ParseReference parses the informed image reference, allowing plain HTTP
+// registries when insecure.
+func (o Options) ParseReference(image string) (name.Reference, error) {
+	nameOpts := []name.Option{}
+	if o.Insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	return name.ParseReference(image, nameOpts...)
+}
+
+// keychain returns the informed keychain, or the default keychain resolver,
+// which checks the image reference against the available login credentials in
+// the system. The user needs to have done a `docker login` or similar to the
+// respective registry before using the Shipwright CLI.
+func (o Options) keychain() authn.Keychain {
+	if o.Keychain == nil {
+		return authn.DefaultKeychain
+	}
+	return o.Keychain
+}
+
+// ContentTag returns the tag identifying the bundle by the digest of its
+// contents, a valid tag holding the digest algorithm and hex.
+func ContentTag(hash v1.Hash) string {
+	return fmt.Sprintf("%s-%s", hash.Algorithm, hash.Hex)
+}
+
 // Push bundles the provided local directory into a container image and pushes
-// it to the given registry. For this to work, it relies on valid and working
-// container registry access credentials and tokens to be available in the
-// local system, for example logins done by `docker login` or similar.
-func Push(ctx context.Context, io *genericclioptions.IOStreams, localDirectory string, targetImage string) (name.Digest, error) {
-	tag, err := name.NewTag(targetImage)
+// it to the repository of the given image. The tarball decides which entries
+// of the local directory are part of the bundle, the same way they are
+// selected for the streaming upload. The bundle is pushed under a tag derived
+// from its contents, instead of the tag of the given image, so concurrent
+// uploads never overwrite each other, and the returned digest pins the exact
+// bundle pushed. When the registry has the same bundle already, the push is
+// skipped and the existing bundle is reused. The registry credentials are
+// resolved by the keychain in the options, by default the credentials
+// available in the local system, for example logins done by `docker login` or
+// similar.
+func Push(ctx context.Context, io *genericclioptions.IOStreams, tarball *streamer.Tar, targetImage string, opts Options) (name.Digest, error) {
+	ref, err := opts.ParseReference(targetImage)
 	if err != nil {
 		return name.Digest{}, err
 	}
+	repo := ref.Context()
 
-	// The default keychain resolver takes the provided image reference and
-	// checks it against the available login credentials in the system. The
-	// needs to have done a `docker login` or similar to the respective
-	// registry before using the Shipwright CLI.
-	auth, err := authn.DefaultKeychain.Resolve(tag.Context())
+	auth, err := opts.keychain().Resolve(repo)
 	if err != nil {
 		return name.Digest{}, err
 	}
@@ -104,15 +189,46 @@ func Push(ctx context.Context, io *genericclioptions.IOStreams, localDirectory s
 		}
 	}()
 
-	fmt.Fprintf(io.Out, "Bundling %q as %q ...\n", localDirectory, targetImage)
-	digest, err := buildbundle.PackAndPush(
+	// the tarball is spooled on disk while the bundle is pushed
+	spoolDir, err := os.MkdirTemp("", "shp-bundle-")
+	if err != nil {
+		done <- struct{}{}
+		return name.Digest{}, err
+	}
+	defer os.RemoveAll(spoolDir)
+
+	image, err := pack(tarball, spoolDir)
+	if err != nil {
+		done <- struct{}{}
+		return name.Digest{}, err
+	}
+	hash, err := image.Digest()
+	if err != nil {
+		done <- struct{}{}
+		return name.Digest{}, err
+	}
+	digest := repo.Digest(hash.String())
+
+	// an identical bundle pushed before is reused, the registry is checked for the digest
+	if _, err = remote.Head(digest, remote.WithContext(ctx), remote.WithAuth(auth)); err == nil {
+		done <- struct{}{}
+		fmt.Fprintf(io.Out, "Source unchanged, reusing bundle %s\n", hash.String())
+		return digest, nil
+	}
+
+	tag := repo.Tag(ContentTag(hash))
+	fmt.Fprintf(io.Out, "Bundling %q as %q ...\n", tarball.Src(), tag.Name())
+
+	err = remote.Write(
 		tag,
-		localDirectory,
+		image,
 		remote.WithContext(ctx),
 		remote.WithAuth(auth),
 		remote.WithProgress(updates),
 	)
-
 	done <- struct{}{}
-	return digest, err
+	if err != nil {
+		return name.Digest{}, err
+	}
+	return digest, nil
 }
@@ -0,0 +1,111 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// sourceDir creates a local directory with a few files, modified at the informed time.
+func sourceDir(g *WithT, dir string, mtime time.Time) string {
+	for name, contents := range map[string]string{
+		"main.go":     "package main",
+		"pkg/file.go": "package pkg",
+	} {
+		fpath := filepath.Join(dir, name)
+		g.Expect(os.MkdirAll(filepath.Dir(fpath), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(fpath, []byte(contents), 0o644)).To(Succeed())
+		g.Expect(os.Chtimes(fpath, mtime, mtime)).To(Succeed())
+	}
+	return dir
+}
+
+func TestPackReproducible(t *testing.T) {
+	g := NewWithT(t)
+
+	digests := []string{}
+	for _, mtime := range []time.Time{time.Now(), time.Now().Add(-time.Hour)} {
+		tarball, err := streamer.NewTar(sourceDir(g, t.TempDir(), mtime))
+		g.Expect(err).To(BeNil())
+		tarball.WithGitConfig(streamer.GitConfig{})
+		image, err := pack(tarball, t.TempDir())
+		g.Expect(err).To(BeNil())
+		hash, err := image.Digest()
+		g.Expect(err).To(BeNil())
+		digests = append(digests, hash.String())
+	}
+	g.Expect(digests[0]).To(Equal(digests[1]))
+}
+
+func TestPackKeepsTarSettings(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := sourceDir(g, t.TempDir(), time.Now())
+	g.Expect(os.Symlink("main.go", filepath.Join(dir, "link.go"))).To(Succeed())
+	tarball, err := streamer.NewTar(dir)
+	g.Expect(err).To(BeNil())
+	tarball.WithGitConfig(streamer.GitConfig{})
+	_, err = pack(tarball, t.TempDir())
+	g.Expect(err).To(BeNil())
+
+	// the bundle dereferences symbolic links, the tarball informed keeps writing them as links
+	var buf bytes.Buffer
+	g.Expect(tarball.Create(&buf)).To(Succeed())
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		g.Expect(err).To(BeNil())
+		if header.Name == "link.go" {
+			g.Expect(header.Typeflag).To(Equal(byte(tar.TypeSymlink)))
+			break
+		}
+	}
+}
+
+func TestPushReusesExistingBundle(t *testing.T) {
+	g := NewWithT(t)
+
+	// registry which has any manifest, recording the requests modifying it
+	writes := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead && strings.Contains(r.URL.Path, "/manifests/"):
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.Header().Set("Content-Length", "1")
+			w.WriteHeader(http.StatusOK)
+		default:
+			writes++
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer registry.Close()
+
+	tarball, err := streamer.NewTar(sourceDir(g, t.TempDir(), time.Now()))
+	g.Expect(err).To(BeNil())
+	tarball.WithGitConfig(streamer.GitConfig{})
+	out := &bytes.Buffer{}
+	ioStreams := &genericclioptions.IOStreams{Out: out, ErrOut: out}
+	image := strings.TrimPrefix(registry.URL, "http://") + "/source/bundle:latest"
+
+	digest, err := Push(context.TODO(), ioStreams, tarball, image, Options{Keychain: NewBasicKeychain("u", "p")})
+	g.Expect(err).To(BeNil())
+	g.Expect(writes).To(Equal(0))
+	g.Expect(digest.DigestStr()).To(HavePrefix("sha256:"))
+	g.Expect(out.String()).To(ContainSubstring("Source unchanged, reusing bundle " + digest.DigestStr()))
+}
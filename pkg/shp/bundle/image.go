@@ -0,0 +1,131 @@
+package bundle
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+// Details describes a bundle image, as shown by inspecting it.
+type Details struct {
+	Digest      v1.Hash           // image manifest digest
+	Size        int64             // total size of the manifest, config and layers, in bytes
+	Layers      int               // amount of layers
+	Created     time.Time         // image creation time
+	Annotations map[string]string // image manifest annotations
+}
+
+// GetSourceBundleDigest returns the source bundle image of the provided
+// buildrun pinned to the digest it has pulled, as recorded on the status
+// sources. A source bundle image informed by digest is returned as is, when
+// the buildrun status has no digest yet.
+func GetSourceBundleDigest(ctx context.Context, client buildclientset.Interface, buildRun *buildv1alpha1.BuildRun) (string, error) {
+	image, err := GetSourceBundleImage(ctx, client, buildRun)
+	if err != nil {
+		return "", err
+	}
+	if image == "" {
+		return "", fmt.Errorf("BuildRun '%s' does not use a source bundle", buildRun.GetName())
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	for _, source := range buildRun.Status.Sources {
+		if source.Bundle != nil && source.Bundle.Digest != "" {
+			return ref.Context().Digest(source.Bundle.Digest).Name(), nil
+		}
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.Name(), nil
+	}
+	return "", fmt.Errorf("BuildRun '%s' has no source bundle digest recorded yet", buildRun.GetName())
+}
+
+// Fetch retrieves the bundle image from the registry, the image contents are
+// only downloaded when read.
+func Fetch(ctx context.Context, image string, opts Options) (v1.Image, error) {
+	ref, err := opts.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	return remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(opts.keychain()))
+}
+
+// Inspect describes the bundle image.
+func Inspect(img v1.Image) (*Details, error) {
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	size, err := img.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	size += manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return &Details{
+		Digest:      digest,
+		Size:        size,
+		Layers:      len(manifest.Layers),
+		Created:     config.Created.Time,
+		Annotations: manifest.Annotations,
+	}, nil
+}
+
+// ListFiles returns the bundle image entries, as the layers are unpacked on top of
+// each other.
+func ListFiles(img v1.Image) ([]streamer.Entry, error) {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	entries := []streamer.Entry{}
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, streamer.Entry{
+			Path:     header.Name,
+			Mode:     header.FileInfo().Mode(),
+			Size:     header.Size,
+			Linkname: header.Linkname,
+			ModTime:  header.ModTime,
+		})
+	}
+}
+
+// Extract unpacks the bundle image entries onto the target directory.
+func Extract(img v1.Image, dir string) error {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+	return streamer.ExtractTar(rc, dir)
+}
@@ -0,0 +1,151 @@
+package bundle
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/shipwright-io/cli/test/stub"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInspectListAndExtract(t *testing.T) {
+	g := NewWithT(t)
+
+	tarball, err := streamer.NewTar(sourceDir(g, t.TempDir(), time.Now()))
+	g.Expect(err).To(BeNil())
+	tarball.WithGitConfig(streamer.GitConfig{})
+	image, err := pack(tarball, t.TempDir())
+	g.Expect(err).To(BeNil())
+
+	details, err := Inspect(image)
+	g.Expect(err).To(BeNil())
+	hash, err := image.Digest()
+	g.Expect(err).To(BeNil())
+	g.Expect(details.Digest).To(Equal(hash))
+	g.Expect(details.Layers).To(Equal(1))
+	g.Expect(details.Size).To(BeNumerically(">", 0))
+	g.Expect(details.Created.Unix()).To(Equal(int64(0)))
+
+	entries, err := ListFiles(image)
+	g.Expect(err).To(BeNil())
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Path)
+	}
+	g.Expect(names).To(ContainElements("main.go", "pkg/file.go"))
+
+	dir := t.TempDir()
+	g.Expect(Extract(image, dir)).To(Succeed())
+	data, err := os.ReadFile(filepath.Join(dir, "pkg", "file.go"))
+	g.Expect(err).To(BeNil())
+	g.Expect(string(data)).To(Equal("package pkg"))
+}
+
+func TestExtractEscapingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+		err     string
+	}{{
+		name:    "entry on the parent directory",
+		headers: []*tar.Header{{Typeflag: tar.TypeReg, Name: "../escape.txt", Mode: 0o644, Size: 1}},
+		err:     "points outside of",
+	}, {
+		name: "hard link to a file on the parent directory",
+		headers: []*tar.Header{
+			{Typeflag: tar.TypeLink, Name: "escape.txt", Linkname: "../secret.txt", Mode: 0o644},
+		},
+		err: "points outside of",
+	}, {
+		// the image flattening drops the entries below a path taken by a non-directory entry
+		name: "file through a symbolic link to the parent directory",
+		headers: []*tar.Header{
+			{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "..", Mode: 0o777},
+			{Typeflag: tar.TypeReg, Name: "link/escape.txt", Mode: 0o644, Size: 1},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			outside := t.TempDir()
+			g.Expect(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644)).To(Succeed())
+			dir := filepath.Join(outside, "dir")
+			g.Expect(os.Mkdir(dir, 0o755)).To(Succeed())
+
+			image, err := stub.LayerImage(tt.headers...)
+			g.Expect(err).To(BeNil())
+			err = Extract(image, dir)
+			if tt.err != "" {
+				g.Expect(err).ToNot(BeNil())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+			g.Expect(filepath.Join(outside, "escape.txt")).ToNot(BeAnExistingFile())
+			g.Expect(filepath.Join(dir, "escape.txt")).ToNot(BeAnExistingFile())
+		})
+	}
+}
+
+func TestGetSourceBundleDigest(t *testing.T) {
+	const digest = "sha256:4a5b1e9a3ef5b4c5e0b8a4b5c7b7f6b1f3e0f2d1b1e4b0e2f8a5c6e1a5b2d3c4"
+
+	buildRun := func(image string, sources ...buildv1alpha1.SourceResult) *buildv1alpha1.BuildRun {
+		return &buildv1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+			Spec: buildv1alpha1.BuildRunSpec{BuildSpec: &buildv1alpha1.BuildSpec{
+				Source: buildv1alpha1.Source{BundleContainer: &buildv1alpha1.BundleContainer{Image: image}},
+			}},
+			Status: buildv1alpha1.BuildRunStatus{Sources: sources},
+		}
+	}
+	bundleResult := buildv1alpha1.SourceResult{Name: "default", Bundle: &buildv1alpha1.BundleSourceResult{Digest: digest}}
+
+	tests := []struct {
+		name     string
+		buildRun *buildv1alpha1.BuildRun
+		image    string
+		err      string
+	}{{
+		name:     "digest on the status",
+		buildRun: buildRun("registry.local/source:latest", bundleResult),
+		image:    "registry.local/source@" + digest,
+	}, {
+		name:     "image pinned to a digest",
+		buildRun: buildRun("registry.local/source@" + digest),
+		image:    "registry.local/source@" + digest,
+	}, {
+		name:     "no digest recorded",
+		buildRun: buildRun("registry.local/source:latest"),
+		err:      "BuildRun 'br' has no source bundle digest recorded yet",
+	}, {
+		name:     "no source bundle",
+		buildRun: &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: "br"}},
+		err:      "BuildRun 'br' does not use a source bundle",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			image, err := GetSourceBundleDigest(context.TODO(), shpfake.NewSimpleClientset(), tt.buildRun)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(image).To(Equal(tt.image))
+		})
+	}
+}
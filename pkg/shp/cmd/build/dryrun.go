@@ -0,0 +1,84 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+// dirSummary accumulates the files and size of a directory, including its subdirectories.
+type dirSummary struct {
+	files int
+	size  int64
+}
+
+// printTree prints the directories that would be uploaded as a tree, each one with the amount of
+// files and total size, including its subdirectories.
+func printTree(w io.Writer, entries []streamer.Entry) error {
+	summaries := map[string]*dirSummary{".": {}}
+	for _, e := range entries {
+		if e.Mode.IsDir() {
+			if _, ok := summaries[e.Path]; !ok {
+				summaries[e.Path] = &dirSummary{}
+			}
+			continue
+		}
+		if !e.Mode.IsRegular() {
+			continue
+		}
+		// accounting the file on all parent directories, creating the ones not listed, which is
+		// the case for entries inside of skipped directories brought back by include patterns
+		for dir := path.Dir(e.Path); ; dir = path.Dir(dir) {
+			s, ok := summaries[dir]
+			if !ok {
+				s = &dirSummary{}
+				summaries[dir] = s
+			}
+			s.files++
+			s.size += e.Size
+			if dir == "." {
+				break
+			}
+		}
+	}
+
+	// sorting by path components, so subdirectories are right after their parent
+	dirs := make([]string, 0, len(summaries))
+	for dir := range summaries {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i] == "." || dirs[j] == "." {
+			return dirs[i] == "."
+		}
+		a, b := strings.Split(dirs[i], "/"), strings.Split(dirs[j], "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, dir := range dirs {
+		s := summaries[dir]
+		name, depth := "./", 0
+		if dir != "." {
+			name, depth = path.Base(dir)+"/", strings.Count(dir, "/")+1
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\n",
+			strings.Repeat("  ", depth), name, streamer.HumanBytes(s.size), streamer.FilesCount(s.files))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	root := summaries["."]
+	_, err := fmt.Fprintf(w, "Total: %s, %s\n", streamer.FilesCount(root.files), streamer.HumanBytes(root.size))
+	return err
+}
@@ -0,0 +1,81 @@
+package build
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestDryRunOutput(t *testing.T) {
+	g := NewWithT(t)
+
+	src := t.TempDir()
+	for name, size := range map[string]int{
+		".gitignore":           6,
+		"main.go":              1024,
+		"cmd/app/app.go":       2048,
+		"cmd/app-tools/x.go":   10,
+		"docs/index.md":        100,
+		"output/generated.bin": 4096,
+	} {
+		fpath := filepath.Join(src, name)
+		g.Expect(os.MkdirAll(filepath.Dir(fpath), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(fpath, bytes.Repeat([]byte("x"), size), 0o644)).To(Succeed())
+	}
+	g.Expect(os.WriteFile(filepath.Join(src, ".gitignore"), []byte("output"), 0o644)).To(Succeed())
+	g.Expect(os.Symlink("main.go", filepath.Join(src, "link.go"))).To(Succeed())
+
+	tarball, err := streamer.NewTar(src)
+	g.Expect(err).To(BeNil())
+	tarball.WithGitConfig(streamer.GitConfig{})
+	entries, err := tarball.List()
+	g.Expect(err).To(BeNil())
+
+	out := &bytes.Buffer{}
+	g.Expect(streamer.PrintFileList(out, entries)).To(Succeed())
+	g.Expect(out.String()).To(Equal("" +
+		"       6 B  .gitignore\n" +
+		"   2.0 KiB  cmd/app/app.go\n" +
+		"      10 B  cmd/app-tools/x.go\n" +
+		"     100 B  docs/index.md\n" +
+		"      link  link.go -> main.go\n" +
+		"   1.0 KiB  main.go\n" +
+		"Total: 5 files, 3.1 KiB\n",
+	))
+
+	out.Reset()
+	g.Expect(printTree(out, entries)).To(Succeed())
+	g.Expect(out.String()).To(Equal("" +
+		"./              3.1 KiB  5 files\n" +
+		"  cmd/          2.0 KiB  2 files\n" +
+		"    app/        2.0 KiB  1 file\n" +
+		"    app-tools/  10 B     1 file\n" +
+		"  docs/         100 B    1 file\n" +
+		"Total: 5 files, 3.1 KiB\n",
+	))
+}
+
+func TestUploadMaxSize(t *testing.T) {
+	g := NewWithT(t)
+
+	src := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(src, "data.bin"), bytes.Repeat([]byte("x"), 2048), 0o644)).To(Succeed())
+
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	u := &UploadCommand{sourceDir: src, dryRun: true, gitConfig: &streamer.GitConfig{}}
+	g.Expect(u.maxSize.Set("2Ki")).To(Succeed())
+	g.Expect(u.inspectUpload(&ioStreams)).To(Succeed())
+	g.Expect(out.String()).To(ContainSubstring("Total: 1 file, 2.0 KiB"))
+
+	g.Expect(u.maxSize.Set("1Ki")).To(Succeed())
+	err := u.inspectUpload(&ioStreams)
+	g.Expect(err).ToNot(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("exceeding the maximum size of 1Ki"))
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	"github.com/shipwright-io/cli/pkg/shp/archive"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
 	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 
 	"github.com/spf13/cobra"
 
@@ -27,6 +29,12 @@ type RunCommand struct {
 	buildRunSpec *buildv1alpha1.BuildRunSpec // stores command-line flags
 	follow       bool                        // flag to tail pod logs
 	follower     *follower.Follower
+	logFormat    tail.Format    // log output format
+	colorMode    tail.ColorMode // colorize log output
+	timestamps   bool           // show elapsed time on log lines
+	archiveLogs  bool           // store logs on the local archive
+	events       bool           // show kubernetes events inline
+	saveTo       string         // directory to export logs to
 }
 
 const buildRunLongDesc = `
@@ -99,6 +107,20 @@ func (r *RunCommand) Run(params *params.Params, ioStreams *genericclioptions.IOS
 			return err
 		}
 	}
+	if err = r.follower.SetLogFormat(r.logFormat); err != nil {
+		return err
+	}
+	r.follower.SetColor(tail.ColorEnabled(r.colorMode, ioStreams.Out))
+	r.follower.SetTimestamps(r.timestamps)
+	r.follower.SetEvents(r.events)
+
+	archives, err := archive.Targets(r.archiveLogs, r.saveTo)
+	if err != nil {
+		return err
+	}
+	for _, a := range archives {
+		r.follower.WithArchive(a)
+	}
 
 	// instantiating a pod watcher with a specific label-selector to find the indented pod where the
 	// actual build started by this subcommand is being executed, including the randomized buildrun
@@ -122,7 +144,15 @@ func runCmd() runner.SubCommand {
 	runCommand := &RunCommand{
 		cmd:          cmd,
 		buildRunSpec: flags.BuildRunSpecFromFlags(cmd.Flags()),
+		logFormat:    tail.FormatText,
+		colorMode:    tail.ColorAuto,
 	}
 	flags.FollowFlag(cmd.Flags(), &runCommand.follow)
+	flags.LogFormatFlag(cmd.Flags(), &runCommand.logFormat)
+	flags.ColorFlag(cmd.Flags(), &runCommand.colorMode)
+	flags.TimestampsFlag(cmd.Flags(), &runCommand.timestamps)
+	flags.ArchiveLogsFlag(cmd.Flags(), &runCommand.archiveLogs)
+	flags.SaveToFlag(cmd.Flags(), &runCommand.saveTo)
+	flags.EventsFlag(cmd.Flags(), &runCommand.events)
 	return runCommand
 }
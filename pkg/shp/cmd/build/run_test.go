@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
@@ -184,6 +185,14 @@ func TestStartBuildRunFollowLog(t *testing.T) {
 					Status: corev1.ConditionFalse,
 				},
 			}
+		case test.phase == corev1.PodFailed:
+			// the BuildRun is marked as failed together with its pod, the failure is reported then
+			br.Status.Conditions = []buildv1alpha1.Condition{
+				{
+					Type:   buildv1alpha1.Succeeded,
+					Status: corev1.ConditionFalse,
+				},
+			}
 		}
 
 		cmd.Complete(param, &ioStreams, []string{name})
@@ -212,10 +221,20 @@ func TestStartBuildRunFollowLog(t *testing.T) {
 }
 
 func checkLog(name, text string, cmd *RunCommand, out *bytes.Buffer, t *testing.T) {
-	// need to employ log lock since accessing same iostream out used by Run cmd
-	cmd.follower.GetLogLock().Lock()
-	defer cmd.follower.GetLogLock().Unlock()
-	if !strings.Contains(out.String(), text) {
-		t.Errorf("test %s: unexpected output: %s", name, out.String())
+	// the outcome may be reported asynchronously, as when the BuildRun final state is observed
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// need to employ log lock since accessing same iostream out used by Run cmd
+		cmd.follower.GetLogLock().Lock()
+		output := out.String()
+		cmd.follower.GetLogLock().Unlock()
+		if strings.Contains(output, text) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("test %s: unexpected output: %s", name, output)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
 	}
 }
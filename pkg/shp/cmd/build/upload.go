@@ -1,15 +1,25 @@
 package build
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"os"
 	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
-	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/shipwright-io/cli/pkg/shp/archive"
 	"github.com/shipwright-io/cli/pkg/shp/bundle"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
@@ -17,11 +27,16 @@ import (
 	"github.com/shipwright-io/cli/pkg/shp/params"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
 	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/util/interrupt"
 )
 
 // UploadCommand represents the "build upload" subcommand, implements runner.SubCommand interface.
@@ -29,14 +44,52 @@ type UploadCommand struct {
 	cmd          *cobra.Command              // cobra command instance
 	buildRunSpec *buildv1alpha1.BuildRunSpec // command-line flags stored directly on the BuildRun
 	follow       bool                        // flag to tail pod logs
-
-	buildRefName string // build name
-	sourceDir    string // local directory to be streamed
-
-	dataStreamer    *streamer.Streamer // tar streamer instance
-	streamingIsDone bool               // marks the streaming is completed
-
-	sourceBundleImage string // image to be used as the source bundle
+	logFormat    tail.Format                 // log output format
+	colorMode    tail.ColorMode              // colorize log output
+	timestamps   bool                        // show elapsed time on log lines
+	archiveLogs  bool                        // store logs on the local archive
+	events       bool                        // show kubernetes events inline
+	saveTo       string                      // directory to export logs to
+
+	buildRefName string                   // build name
+	sourceDir    string                   // local directory to be streamed
+	excludes     []string                 // patterns of local files to skip
+	includes     []string                 // patterns of local files to upload even when ignored
+	noGitIgnore  bool                     // upload local files ignored by git
+	gitRef       string                   // git ref whose committed tree is uploaded
+	gitCommit    *streamer.GitCommit      // commit resolved from the git ref
+	extractDir   string                   // temporary directory holding the committed tree or archive
+	buildOutput  *buildv1alpha1.Image     // Build's output image, base for the BuildRun output labels
+	buildSpec    *buildv1alpha1.BuildSpec // Build's specification, embedded to pin the source bundle
+
+	compression streamer.Compression   // compression applied on the data streamed
+	verbose     bool                   // list the files extracted on the build pod
+	dryRun      bool                   // list the local files instead of uploading
+	tree        bool                   // show a directory tree summary on dry-run
+	maxSize     resource.QuantityValue // maximum size of the local files uploaded
+	progressOut io.Writer              // writer to report the upload progress
+	gitConfig   *streamer.GitConfig    // user git configuration locations, the current user's when not set
+
+	dataStreamer      *streamer.Streamer       // tar streamer instance
+	uploadIsDone      bool                     // marks the data upload is completed
+	uploadLock        sync.Mutex               // guards the upload completion and the build pod issue
+	podErr            error                    // build pod issue found before the upload is completed
+	startTimeout      time.Duration            // wait for the build pod to receive the data, when set
+	keepOnFailure     bool                     // keep the BuildRun when the upload fails
+	watch             bool                     // upload again when the local files change
+	ctx               context.Context          // context of the current upload
+	buildRunName      string                   // BuildRun receiving the data upload
+	buildRunNamespace string                   // BuildRun namespace
+	shpClientset      buildclientset.Interface // shipwright client to annotate the BuildRun
+
+	sourceBundleImage       string         // image to be used as the source bundle
+	bundleDigest            name.Digest    // source bundle pushed, pinned on the BuildRun
+	bundleCredentialsSecret string         // secret with the registry credentials to push the bundle
+	registryConfig          string         // docker configuration file with the registry credentials
+	registryUsername        string         // registry username to push the bundle
+	registryPasswordStdin   bool           // read the registry password from stdin
+	insecureRegistry        bool           // push the bundle to a plain HTTP registry
+	bundleOpts              bundle.Options // registry settings to push the bundle
 
 	pw       *reactor.PodWatcher // pod-watcher instance
 	follower *follower.Follower  // follower instance
@@ -49,23 +102,94 @@ to be used for the Build. Two options are supported: streaming and bundling. Wit
 employ Shipwright Builds from a local repository clone.
 
 When streaming is used, the Build Controller waits for the data being streamed to the build pod,
-instead of executing "git clone". The upload skips the ".git" directory completely, and it follows
-the ".gitignore" directives, when the file is found at the root of the directory uploaded.
+instead of executing "git clone". The data is streamed once the source waiter container, found by
+its name, image or "waiter start" command-line, is running, and it's extracted on the
+container's "source" workspace volume mount, "/workspace/source" by default. The data streamed is compressed with "--compression", by default
+the best compression supported by the "tar" on the build pod is used. The upload progress is shown,
+or the files extracted on the build pod are listed with "--verbose". Once extracted, the data is
+verified against a SHA-256 manifest of the files uploaded, and the manifest digest is recorded on
+the BuildRun annotation "buildrun.shipwright.io/source-manifest-digest".
+
+The upload skips the ".git" directory completely, and it follows the same ignore rules as git:
+".gitignore" files in any directory, ".git/info/exclude" and the global "core.excludesFile", unless
+"--no-gitignore" is informed. The ".shpignore" file at the root of the directory uploaded is
+followed as well. Additional entries are skipped with "--exclude", and ignored entries are uploaded
+anyway with "--include", both using the gitignore syntax and applying to streaming and bundling.
+Directories, including empty ones, symbolic links and file modes are preserved, symbolic links
+pointing outside of the directory uploaded are rejected.
+
+With "--git-ref", the committed tree of the informed ref (branch, tag or commit SHA) on the local
+repository is uploaded instead of the working directory, like "git archive" does, excluding any
+uncommitted and untracked files. The commit SHA is recorded on the BuildRun annotation
+"buildrun.shipwright.io/source-git-commit" and on the output image label
+"org.opencontainers.image.revision". When the ref is the commit checked out, whether the working
+directory had changes is recorded as well, on "buildrun.shipwright.io/source-git-dirty" and
+"io.shipwright.source.dirty".
+
+The directory argument can also be a ".tar", ".tar.gz", ".tgz" or ".zip" archive, produced by an
+earlier CI stage for instance. The archive root is uploaded like the root of a directory, thus the
+ignore rules, "--exclude", "--include" and the Build's context directory apply the same way, and it
+is repackaged for bundle Builds.
+
+With "--watch", the directory is watched for changes on the files that would be uploaded, and after
+a short quiet interval a new BuildRun is uploaded and its logs are followed, canceling the BuildRun
+still running for the previous changes. A summary with the duration and the output image digest is
+shown after each BuildRun.
+
+Use "--dry-run" to list the files that would be uploaded, or a tree of directories with their sizes
+adding "--tree", without creating a BuildRun. The upload is aborted when the files exceed the size
+informed with "--max-size".
+
+When the upload fails, or it's interrupted with Ctrl-C before the data is uploaded, the BuildRun is
+deleted, the same happens when the build pod does not receive the data before the request timeout,
+or within 10 minutes when "--request-timeout" is not informed. Use "--keep-on-failure" to keep the
+BuildRun for inspection.
 
 In case a source bundle image is defined, the bundling feature is used, which will bundle the local
 source code into a bundle container and upload it to the specified container registry. Instead of
 executing using Git in the source step, it will use the container registry to obtain the source code.
+Bundles only hold directories and regular files, symbolic links are replaced by the file they point
+to, and symbolic links to directories are rejected. The registry credentials are the ones available
+on the local system, like after "docker login", unless informed by a "kubernetes.io/dockerconfigjson"
+secret with "--bundle-credentials-secret", a Docker configuration file with "--registry-config", or
+with "--registry-username" and the password on stdin with "--registry-password-stdin". Plain HTTP
+registries are employed with "--insecure-registry". Each bundle is pushed to the repository of the
+Build's bundle image under a tag derived from its contents, "sha256-<hex>", so concurrent uploads
+don't overwrite each other, and the BuildRun embeds the Build specification with the bundle image
+pinned to the digest pushed, recorded on the "buildrun.shipwright.io/source-bundle-digest"
+annotation as well. Bundles are reproducible, thus when the registry has the same bundle already,
+from a previous upload of the same files, it's reused instead of pushed again.
 
 	$ shp buildrun upload <build-name>
 	$ shp buildrun upload <build-name> /path/to/repository
+	$ shp buildrun upload <build-name> --git-ref HEAD
+	$ shp buildrun upload <build-name> /path/to/source.tar.gz
+	$ shp buildrun upload <build-name> --watch
+	$ shp buildrun upload <build-name> --bundle-credentials-secret <secret-name>
+	$ shp buildrun upload <build-name> --dry-run --tree
 `
 
-	// targetBaseDir directory where data will be uploaded.
-	targetBaseDir = "/workspace/source"
+	// cleanupTimeout timeout to delete the BuildRun when the upload is not completed.
+	cleanupTimeout = 30 * time.Second
+	// startTimeout timeout for the build pod to receive the data, when the request timeout is not
+	// informed.
+	startTimeout = 10 * time.Minute
 	// buildNameAnnotation label to identify the Build name.
 	buildNameAnnotation = "build.shipwright.io/name"
 	// buildRunNameAnnotation label to identify the BuildRun name.
 	buildRunNameAnnotation = "buildrun.shipwright.io/name"
+	// sourceManifestDigestAnnotation annotation to record the digest of the streamed files manifest.
+	sourceManifestDigestAnnotation = "buildrun.shipwright.io/source-manifest-digest"
+	// sourceBundleDigestAnnotation annotation to record the source bundle digest pushed.
+	sourceBundleDigestAnnotation = "buildrun.shipwright.io/source-bundle-digest"
+	// sourceGitCommitAnnotation annotation to record the commit SHA uploaded with "--git-ref".
+	sourceGitCommitAnnotation = "buildrun.shipwright.io/source-git-commit"
+	// sourceGitDirtyAnnotation annotation to record whether the working directory had changes.
+	sourceGitDirtyAnnotation = "buildrun.shipwright.io/source-git-dirty"
+	// imageRevisionLabel output image label with the commit SHA uploaded with "--git-ref".
+	imageRevisionLabel = "org.opencontainers.image.revision"
+	// imageDirtyLabel output image label to record whether the working directory had changes.
+	imageDirtyLabel = "io.shipwright.source.dirty"
 )
 
 // Cmd exposes the Cobra command instance.
@@ -119,12 +243,15 @@ func (u *UploadCommand) Complete(p *params.Params, _ *genericclioptions.IOStream
 	if err != nil {
 		return err
 	}
+	u.shpClientset = shpClientSet
 
 	// check that the cluster actually contains a build with this name
 	build, err := shpClientSet.ShipwrightV1alpha1().Builds(p.Namespace()).Get(u.cmd.Context(), u.buildRefName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
+	u.buildOutput = build.Spec.Output.DeepCopy()
+	u.buildSpec = build.Spec.DeepCopy()
 
 	// detect upload method, if build has bundle container image set, it
 	// is assumed that the source bundle upload via registry is used
@@ -133,24 +260,173 @@ func (u *UploadCommand) Complete(p *params.Params, _ *genericclioptions.IOStream
 
 	} else {
 		u.dataStreamer = streamer.NewStreamer(restConfig, clientset)
+		u.dataStreamer.SetCompression(u.compression)
+		u.dataStreamer.SetVerbose(u.verbose)
 	}
 
 	u.pw, err = p.NewPodWatcher(u.Cmd().Context())
-	return err
+	if err != nil {
+		return err
+	}
+	// without a request timeout, the wait for the build pod to receive the data is still bounded
+	if to, err := p.RequestTimeout(); err == nil && to == math.MaxInt64 {
+		u.startTimeout = startTimeout
+	}
+	return nil
 }
 
-// Validate the current subcommand state, make sure the directory to be uploaded exists.
+// Validate the current subcommand state, make sure the directory or archive to be uploaded exists.
 func (u *UploadCommand) Validate() error {
 	stat, err := os.Stat(u.sourceDir)
 	if err != nil {
 		return err
 	}
-	if !stat.IsDir() {
-		return fmt.Errorf("informed path is not a directory: '%s'", u.sourceDir)
+	if !stat.IsDir() && !(stat.Mode().IsRegular() && streamer.IsArchive(u.sourceDir)) {
+		return fmt.Errorf("informed path is not a directory or a supported archive: '%s'", u.sourceDir)
+	}
+	if strings.HasPrefix(u.gitRef, "-") {
+		return fmt.Errorf("informed git ref is invalid: '%s'", u.gitRef)
+	}
+	if u.gitRef != "" && !stat.IsDir() {
+		return fmt.Errorf("git ref can only be informed for a directory: '%s'", u.sourceDir)
+	}
+	if u.watch && (u.gitRef != "" || !stat.IsDir() || u.dryRun) {
+		return fmt.Errorf("watch can only be informed for a directory, without git ref or dry-run")
+	}
+
+	credentials := 0
+	for _, informed := range []bool{u.bundleCredentialsSecret != "", u.registryConfig != "", u.registryUsername != ""} {
+		if informed {
+			credentials++
+		}
+	}
+	if credentials > 1 {
+		return fmt.Errorf("only one of bundle credentials secret, registry config or registry username can be informed")
+	}
+	if (u.registryUsername != "") != u.registryPasswordStdin {
+		return fmt.Errorf("registry username and password-stdin must be informed together")
 	}
 	return nil
 }
 
+// hasRegistryOptions checks if any of the registry options to push the source bundle is informed.
+func (u *UploadCommand) hasRegistryOptions() bool {
+	return u.bundleCredentialsSecret != "" || u.registryConfig != "" || u.registryUsername != "" ||
+		u.insecureRegistry
+}
+
+// resolveBundleOptions prepares the registry settings to push the source bundle, the credentials
+// are read from the informed secret, Docker configuration file, or username and password from
+// stdin, otherwise the credentials available on the local system are employed.
+func (u *UploadCommand) resolveBundleOptions(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	if u.sourceBundleImage == "" {
+		if u.hasRegistryOptions() {
+			return fmt.Errorf("registry options only apply to Builds using a source bundle, '%s' does not",
+				u.buildRefName)
+		}
+		return nil
+	}
+
+	u.bundleOpts = bundle.Options{Insecure: u.insecureRegistry}
+	var err error
+	switch {
+	case u.registryUsername != "":
+		var password []byte
+		if password, err = io.ReadAll(ioStreams.In); err != nil {
+			return fmt.Errorf("unable to read the registry password from stdin: %w", err)
+		}
+		u.bundleOpts.Keychain = bundle.NewBasicKeychain(u.registryUsername, strings.TrimRight(string(password), "\r\n"))
+
+	case u.bundleCredentialsSecret != "":
+		var clientset kubernetes.Interface
+		if clientset, err = p.ClientSet(); err != nil {
+			return err
+		}
+		var secret *corev1.Secret
+		secret, err = clientset.CoreV1().Secrets(p.Namespace()).
+			Get(u.cmd.Context(), u.bundleCredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if secret.Type != corev1.SecretTypeDockerConfigJson {
+			return fmt.Errorf("secret '%s' is not of type '%s'", secret.GetName(), corev1.SecretTypeDockerConfigJson)
+		}
+		u.bundleOpts.Keychain, err = bundle.NewDockerConfigKeychain(
+			bytes.NewReader(secret.Data[corev1.DockerConfigJsonKey]),
+		)
+
+	case u.registryConfig != "":
+		u.bundleOpts.Keychain, err = bundle.NewDockerConfigFileKeychain(u.registryConfig)
+	}
+	return err
+}
+
+// extractArchive extracts the source archive informed on a temporary directory, which is the
+// source of the upload.
+func (u *UploadCommand) extractArchive() error {
+	dir, err := os.MkdirTemp("", "shp-upload-")
+	if err != nil {
+		return err
+	}
+	u.extractDir = dir
+	log.Printf("Extracting '%s'...", u.sourceDir)
+	return streamer.ExtractArchive(u.sourceDir, dir)
+}
+
+// exportGitRef resolves the git ref informed, and exports its committed tree on a temporary
+// directory, which is the source of the upload instead of the working directory.
+func (u *UploadCommand) exportGitRef() error {
+	commit, err := streamer.ResolveGitRef(u.sourceDir, u.gitRef)
+	if err != nil {
+		return fmt.Errorf("unable to resolve git ref '%s' on '%s': %w", u.gitRef, u.sourceDir, err)
+	}
+	dir, err := os.MkdirTemp("", "shp-upload-")
+	if err != nil {
+		return err
+	}
+	u.extractDir = dir
+	if err = streamer.ExportGitTree(u.sourceDir, commit, dir); err != nil {
+		return err
+	}
+	u.gitCommit = commit
+
+	if commit.Dirty {
+		log.Printf("Uploading commit '%s' from '%s', which has uncommitted changes not included", commit.SHA, u.gitRef)
+	} else {
+		log.Printf("Uploading commit '%s' from '%s'", commit.SHA, u.gitRef)
+	}
+	return nil
+}
+
+// recordGitCommit records the commit uploaded on the BuildRun annotations, and on the output image
+// labels. Whether the working directory had changes is only recorded for the commit checked out.
+// When the output image is not informed on the BuildRun, the Build's output is employed, since the
+// BuildRun output replaces the Build's.
+func (u *UploadCommand) recordGitCommit(br *buildv1alpha1.BuildRun) {
+	if u.gitCommit == nil {
+		return
+	}
+	dirty := strconv.FormatBool(u.gitCommit.Dirty)
+	metav1.SetMetaDataAnnotation(&br.ObjectMeta, sourceGitCommitAnnotation, u.gitCommit.SHA)
+	if u.gitCommit.Head {
+		metav1.SetMetaDataAnnotation(&br.ObjectMeta, sourceGitDirtyAnnotation, dirty)
+	}
+
+	if br.Spec.Output == nil || br.Spec.Output.Image == "" {
+		if u.buildOutput == nil || u.buildOutput.Image == "" {
+			return
+		}
+		br.Spec.Output = u.buildOutput.DeepCopy()
+	}
+	if br.Spec.Output.Labels == nil {
+		br.Spec.Output.Labels = map[string]string{}
+	}
+	br.Spec.Output.Labels[imageRevisionLabel] = u.gitCommit.SHA
+	if u.gitCommit.Head {
+		br.Spec.Output.Labels[imageDirtyLabel] = dirty
+	}
+}
+
 // createBuildRun creates the BuildRun instance to receive the data upload afterwards, it returns the
 // BuildRun name just created and error.
 func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRun, error) {
@@ -161,9 +437,19 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 		br = &buildv1alpha1.BuildRun{
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: fmt.Sprintf("%s-", u.buildRefName),
+				Annotations:  map[string]string{sourceBundleDigestAnnotation: u.bundleDigest.String()},
+				// the embedded Build specification does not reference the Build, the label keeps the
+				// BuildRun listed with the Build's ones
+				Labels: map[string]string{buildv1alpha1.LabelBuild: u.buildRefName},
 			},
 			Spec: *u.buildRunSpec,
 		}
+		// pinning the source bundle to the digest pushed, embedding the Build specification with
+		// the bundle image replaced, since the BuildRun can't override the Build's source
+		buildSpec := u.buildSpec.DeepCopy()
+		buildSpec.Source.BundleContainer.Image = u.bundleDigest.String()
+		br.Spec.BuildSpec = buildSpec
+		br.Spec.BuildRef = nil
 
 	// Use local copy streaming feature for source upload and build
 	default:
@@ -179,6 +465,7 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 		}
 	}
 
+	u.recordGitCommit(br)
 	flags.SanitizeBuildRunSpec(&br.Spec)
 
 	ns := p.Namespace()
@@ -189,7 +476,7 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 	}
 	br, err = clientset.ShipwrightV1alpha1().
 		BuildRuns(ns).
-		Create(u.cmd.Context(), br, metav1.CreateOptions{})
+		Create(u.ctx, br, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -197,36 +484,208 @@ func (u *UploadCommand) createBuildRun(p *params.Params) (*buildv1alpha1.BuildRu
 	return br, nil
 }
 
+// newTar instantiate the tar helper for the source directory, applying the exclude and include
+// patterns informed by the user. With a git ref, the committed tree is used as is, thus the git
+// ignore patterns are not applied. A source archive is employed like a directory.
+func (u *UploadCommand) newTar() (*streamer.Tar, error) {
+	src := u.sourceDir
+	if u.extractDir != "" {
+		src = u.extractDir
+	}
+	tarball, err := streamer.NewTar(src)
+	if err != nil {
+		return nil, err
+	}
+	if u.gitConfig != nil {
+		tarball.WithGitConfig(*u.gitConfig)
+	}
+	if u.noGitIgnore || u.gitCommit != nil {
+		tarball.WithoutGitIgnore()
+	}
+	return tarball.WithExcludes(u.excludes...).WithIncludes(u.includes...), nil
+}
+
+// inspectUpload lists the local files that would be uploaded, printing them on dry-run, and makes
+// sure their total size is within the maximum size informed.
+func (u *UploadCommand) inspectUpload(ioStreams *genericclioptions.IOStreams) error {
+	tarball, err := u.newTar()
+	if err != nil {
+		return err
+	}
+	// the bundle only holds regular files, symbolic links are replaced by the files
+	if u.sourceBundleImage != "" {
+		tarball.WithDereferenceSymlinks()
+	}
+	entries, err := tarball.List()
+	if err != nil {
+		return err
+	}
+
+	if u.dryRun {
+		printFn := streamer.PrintFileList
+		if u.tree {
+			printFn = printTree
+		}
+		if err = printFn(ioStreams.Out, entries); err != nil {
+			return err
+		}
+	}
+
+	_, size := streamer.Totals(entries)
+	if maxSize := u.maxSize.Value(); maxSize > 0 && size > maxSize {
+		return fmt.Errorf("the local files in '%s' have %s, exceeding the maximum size of %s",
+			u.sourceDir, streamer.HumanBytes(size), u.maxSize.String())
+	}
+	return nil
+}
+
+// annotateBuildRun patches the BuildRun receiving the data upload with the informed annotation.
+func (u *UploadCommand) annotateBuildRun(ns, key, value string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.shpClientset.ShipwrightV1alpha1().
+		BuildRuns(ns).
+		Patch(u.ctx, u.buildRunName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
 // performDataStreaming execute the data transfer process end-to-end.
 func (u *UploadCommand) performDataStreaming(target *streamer.Target) error {
-	if u.streamingIsDone {
+	if u.isUploadDone() {
 		return nil
 	}
 
 	log.Printf("Streaming '%s' to the Build POD '%s'...", u.sourceDir, target.Pod)
 	// creates an in-memory tarball with source directory data, and ready to start data streaming
-	tarball, err := streamer.NewTar(u.sourceDir)
+	tarball, err := u.newTar()
 	if err != nil {
 		return err
 	}
 
+	// reporting the progress based on the files and bytes found beforehand, the file listing of
+	// verbose mode replaces the progress report
+	if !u.verbose {
+		files, size, err := tarball.Scan()
+		if err != nil {
+			return err
+		}
+		progress := streamer.NewProgress(u.progressOut, files, size)
+		tarball.WithProgressFn(progress.Update)
+		defer progress.Done()
+	}
+
 	// start writing the data using the tarball format, and streaming it via STDIN, which is
-	// redirected to the correct container
+	// redirected to the correct container. Streaming only returns when the remote "tar" has exited,
+	// a error means the data could not be extracted
 	if err = u.dataStreamer.Stream(target, tarball.Create); err != nil {
+		return fmt.Errorf("unable to extract '%s' on the Build POD '%s': %w", u.sourceDir, target.Pod, err)
+	}
+	log.Printf("Data streamed, %s", u.dataStreamer.Stats())
+
+	// verifying the extracted files against the manifest generated while creating the tarball, when
+	// the container has the tools needed, and recording its digest on the BuildRun for audit
+	manifest := tarball.Manifest()
+	if u.dataStreamer.CanVerify(target) {
+		if err = u.dataStreamer.Verify(target, manifest); err != nil {
+			return err
+		}
+		log.Printf("Data verified on '%s', manifest digest '%s'", target.BaseDir, manifest.Digest())
+	} else {
+		log.Printf("WARNING: Unable to verify the data on '%s', the container lacks 'sh', 'sha256sum' or 'wc', manifest digest '%s'",
+			target.BaseDir, manifest.Digest())
+	}
+	if err = u.annotateBuildRun(target.Namespace, sourceManifestDigestAnnotation, manifest.Digest()); err != nil {
 		return err
 	}
 
-	// graceful waiting for the container finish writing the streamed data, and right after, calling
-	// done on the container, so the rest of the build process can continue and use streamed data
-	time.Sleep(5 * time.Second)
+	// calling done on the container, so the rest of the build process can continue and use the
+	// streamed data
 	if err = u.dataStreamer.Done(target); err != nil {
 		return err
 	}
 
-	u.streamingIsDone = true
+	u.setUploadDone()
 	return nil
 }
 
+// setUploadDone marks the data upload as completed, from this point on the BuildRun is kept.
+func (u *UploadCommand) setUploadDone() {
+	u.uploadLock.Lock()
+	defer u.uploadLock.Unlock()
+	u.uploadIsDone = true
+}
+
+// isUploadDone checks if the data upload is completed.
+func (u *UploadCommand) isUploadDone() bool {
+	u.uploadLock.Lock()
+	defer u.uploadLock.Unlock()
+	return u.uploadIsDone
+}
+
+// setBuildRun records the BuildRun receiving the data upload.
+func (u *UploadCommand) setBuildRun(br *buildv1alpha1.BuildRun) {
+	u.uploadLock.Lock()
+	defer u.uploadLock.Unlock()
+	u.buildRunName = br.GetName()
+	u.buildRunNamespace = br.GetNamespace()
+}
+
+// cleanupBuildRun deletes the BuildRun created for the upload when the data upload did not
+// complete, either because it has failed or because the command was interrupted, otherwise the
+// BuildRun would wait for the data forever. It's kept when "--keep-on-failure" is informed.
+func (u *UploadCommand) cleanupBuildRun() {
+	u.uploadLock.Lock()
+	done, ns, name := u.uploadIsDone, u.buildRunNamespace, u.buildRunName
+	u.uploadLock.Unlock()
+	if done || name == "" {
+		return
+	}
+	if u.keepOnFailure {
+		log.Printf("Upload not completed, keeping BuildRun '%s'", name)
+		return
+	}
+
+	log.Printf("Upload not completed, deleting BuildRun '%s'...", name)
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+	propagation := metav1.DeletePropagationBackground
+	err := u.shpClientset.ShipwrightV1alpha1().
+		BuildRuns(ns).
+		Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Printf("Unable to delete BuildRun '%s': %v", name, err)
+	}
+}
+
+// onPodTimeout records the build pod has not reached the running state before the context or
+// request timeout has expired, so the upload is considered failed.
+func (u *UploadCommand) onPodTimeout(msg string) {
+	u.uploadLock.Lock()
+	defer u.uploadLock.Unlock()
+	if !u.uploadIsDone {
+		u.podErr = fmt.Errorf("build pod has not received the data upload: %s", msg)
+	}
+}
+
+// onStartTimeout records the build pod has not received the data upload within the start timeout,
+// as when the pod is never created or stays pending, and stops watching over it.
+func (u *UploadCommand) onStartTimeout() {
+	u.uploadLock.Lock()
+	if u.uploadIsDone {
+		u.uploadLock.Unlock()
+		return
+	}
+	u.podErr = fmt.Errorf("build pod has not received the data upload within %s", u.startTimeout)
+	u.uploadLock.Unlock()
+	u.stop()
+}
+
 // stop following logs and watch over pod.
 func (u *UploadCommand) stop() {
 	if u.follower != nil {
@@ -240,12 +699,24 @@ func (u *UploadCommand) stop() {
 func (u *UploadCommand) onPodModifiedEventStreaming(pod *corev1.Pod) error {
 	switch pod.Status.Phase {
 	case corev1.PodRunning:
-		return u.performDataStreaming(&streamer.Target{
-			Namespace: pod.GetNamespace(),
-			Pod:       pod.GetName(),
-			Container: fmt.Sprintf("step-%s", sources.WaiterContainerName),
-			BaseDir:   targetBaseDir,
-		})
+		if u.isUploadDone() {
+			return nil
+		}
+		// discovering the source waiter container, the data is streamed once it's running
+		target, err := streamer.NewTargetFromPod(pod)
+		if err != nil {
+			u.stop()
+			return err
+		}
+		running, err := target.IsRunning(pod)
+		if err != nil {
+			u.stop()
+			return err
+		}
+		if !running {
+			return nil
+		}
+		return u.performDataStreaming(target)
 	case corev1.PodFailed:
 		u.stop()
 		return fmt.Errorf("build pod '%s' has failed", pod.GetName())
@@ -271,47 +742,146 @@ func (u *UploadCommand) onPodModifiedEventBundling(pod *corev1.Pod) error {
 // Run executes the primary business logic of this subcommand, by starting to watch over the build
 // pod status and react accordingly.
 func (u *UploadCommand) Run(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	// exporting the committed tree of the informed git ref, or extracting the source archive, on a
+	// temporary directory removed when the upload is over
+	defer func() {
+		if u.extractDir != "" {
+			os.RemoveAll(u.extractDir)
+		}
+	}()
+	if u.gitRef != "" {
+		if err := u.exportGitRef(); err != nil {
+			return err
+		}
+	} else if streamer.IsArchive(u.sourceDir) {
+		if err := u.extractArchive(); err != nil {
+			return err
+		}
+	}
+
+	if !u.dryRun {
+		if err := u.resolveBundleOptions(p, ioStreams); err != nil {
+			return err
+		}
+	}
+
+	if u.watch {
+		return u.watchUpload(p, ioStreams)
+	}
+	u.ctx = u.cmd.Context()
+	return u.runUpload(p, ioStreams)
+}
+
+// runUpload creates the BuildRun and uploads the local data for it.
+func (u *UploadCommand) runUpload(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	// inspecting the local files before creating the BuildRun, on dry-run nothing else happens
+	if u.dryRun || !u.maxSize.IsZero() {
+		if err := u.inspectUpload(ioStreams); err != nil {
+			return err
+		}
+		if u.dryRun {
+			return nil
+		}
+	}
+
+	// pushing the source bundle before the BuildRun exists, so it's pinned to the bundle digest
+	if u.sourceBundleImage != "" {
+		tarball, err := u.newTar()
+		if err != nil {
+			return err
+		}
+		if u.bundleDigest, err = bundle.Push(u.ctx, ioStreams, tarball, u.sourceBundleImage, u.bundleOpts); err != nil {
+			return err
+		}
+	}
+
 	// creating a BuildRun with settings for the local source upload
 	br, err := u.createBuildRun(p)
 	if err != nil {
 		return err
 	}
+	u.setBuildRun(br)
+	u.progressOut = ioStreams.ErrOut
+
+	// the upload runs as a critical section, when it fails or the user interrupts it before the
+	// data is uploaded, the BuildRun is removed
+	return interrupt.New(nil, u.cleanupBuildRun).Run(func() error {
+		return u.upload(p, ioStreams, br)
+	})
+}
 
+// upload bundles or streams the local data for the BuildRun informed, watching over the build pod
+// and following its logs when requested.
+func (u *UploadCommand) upload(
+	p *params.Params,
+	ioStreams *genericclioptions.IOStreams,
+	br *buildv1alpha1.BuildRun,
+) error {
+	var err error
 	if u.follow {
 		// when follow flag is enabled, instantiating the "follower" to live tail logs
-		if u.follower, err = p.NewFollower(u.Cmd().Context(), types.NamespacedName{Namespace: br.Namespace, Name: br.Name}, ioStreams); err != nil {
+		if u.follower, err = p.NewFollower(u.ctx, types.NamespacedName{Namespace: br.Namespace, Name: br.Name}, ioStreams); err != nil {
+			return err
+		}
+		if err = u.follower.SetLogFormat(u.logFormat); err != nil {
+			return err
+		}
+		u.follower.SetColor(tail.ColorEnabled(u.colorMode, ioStreams.Out))
+		u.follower.SetTimestamps(u.timestamps)
+		u.follower.SetEvents(u.events)
+
+		archives, err := archive.Targets(u.archiveLogs, u.saveTo)
+		if err != nil {
 			return err
 		}
+		for _, a := range archives {
+			u.follower.WithArchive(a)
+		}
 	}
 
 	switch {
 	// Using bundling to upload local source code
 	case u.sourceBundleImage != "":
-		_, err = bundle.Push(u.cmd.Context(), ioStreams, u.sourceDir, u.sourceBundleImage)
-		if err != nil {
-			return err
-		}
+		// the source bundle has been pushed before creating the BuildRun
+		u.setUploadDone()
 
 		u.pw.WithOnPodModifiedFn(u.onPodModifiedEventBundling)
 
 	// Using streaming to upload local source code
 	default:
-		// registering the routine that will react upon build pod state changes
+		// registering the routine that will react upon build pod state changes, and the one
+		// recording the build pod did not receive the data before the timeout
 		u.pw.WithOnPodModifiedFn(u.onPodModifiedEventStreaming)
+		u.pw.WithTimeoutPodFn(u.onPodTimeout)
+		if u.startTimeout > 0 {
+			timer := time.AfterFunc(u.startTimeout, u.onStartTimeout)
+			defer timer.Stop()
+		}
 	}
 
 	// preparing a label-selector with annotations that can pinpoint the exact pod created for the
 	// BuildRun we've just issued
-	labelSelector := fmt.Sprintf(
-		"%s=%s,%s=%s",
-		buildNameAnnotation, u.buildRunSpec.BuildRef.Name,
-		buildRunNameAnnotation, br.Name,
-	)
+	labelSelector := fmt.Sprintf("%s=%s", buildRunNameAnnotation, br.Name)
+	if br.Spec.BuildRef != nil {
+		labelSelector = fmt.Sprintf("%s=%s,%s", buildNameAnnotation, br.Spec.BuildRef.Name, labelSelector)
+	}
 	listOpts := metav1.ListOptions{LabelSelector: labelSelector}
 
+	if u.follower != nil {
+		u.follower.StartWatchers()
+	}
+
 	// starting the event reactor with the ListOptions instance to find the desired pod, as the pod
 	// status changes, different routines are issued
 	_, err = u.pw.Start(listOpts)
+	if err == nil {
+		u.uploadLock.Lock()
+		err = u.podErr
+		u.uploadLock.Unlock()
+	}
+	if err == nil && u.follower != nil {
+		err = u.follower.Err()
+	}
 	return err
 }
 
@@ -327,7 +897,32 @@ func uploadCmd() runner.SubCommand {
 		cmd:          cmd,
 		buildRunSpec: flags.BuildRunSpecFromFlags(cmd.Flags()),
 		follow:       false,
+		logFormat:    tail.FormatText,
+		colorMode:    tail.ColorAuto,
+		compression:  streamer.CompressionAuto,
 	}
 	flags.FollowFlag(cmd.Flags(), &u.follow)
+	flags.LogFormatFlag(cmd.Flags(), &u.logFormat)
+	flags.ColorFlag(cmd.Flags(), &u.colorMode)
+	flags.TimestampsFlag(cmd.Flags(), &u.timestamps)
+	flags.ArchiveLogsFlag(cmd.Flags(), &u.archiveLogs)
+	flags.SaveToFlag(cmd.Flags(), &u.saveTo)
+	flags.EventsFlag(cmd.Flags(), &u.events)
+	flags.ExcludeFlag(cmd.Flags(), &u.excludes)
+	flags.IncludeFlag(cmd.Flags(), &u.includes)
+	flags.NoGitIgnoreFlag(cmd.Flags(), &u.noGitIgnore)
+	flags.GitRefFlag(cmd.Flags(), &u.gitRef)
+	flags.KeepOnFailureFlag(cmd.Flags(), &u.keepOnFailure)
+	flags.WatchFlag(cmd.Flags(), &u.watch)
+	flags.BundleCredentialsSecretFlag(cmd.Flags(), &u.bundleCredentialsSecret)
+	flags.RegistryConfigFlag(cmd.Flags(), &u.registryConfig)
+	flags.RegistryUsernameFlag(cmd.Flags(), &u.registryUsername)
+	flags.RegistryPasswordStdinFlag(cmd.Flags(), &u.registryPasswordStdin)
+	flags.InsecureRegistryFlag(cmd.Flags(), &u.insecureRegistry)
+	flags.CompressionFlag(cmd.Flags(), &u.compression)
+	flags.VerboseFlag(cmd.Flags(), &u.verbose)
+	flags.DryRunFlag(cmd.Flags(), &u.dryRun)
+	flags.TreeFlag(cmd.Flags(), &u.tree)
+	flags.MaxSizeFlag(cmd.Flags(), &u.maxSize)
 	return u
 }
@@ -0,0 +1,250 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestUploadRecordGitCommit(t *testing.T) {
+	g := NewWithT(t)
+
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	u := &UploadCommand{
+		gitCommit: &streamer.GitCommit{SHA: sha, Head: true, Dirty: true},
+		buildOutput: &buildv1alpha1.Image{
+			Image:  "registry.local/app",
+			Labels: map[string]string{"team": "a"},
+		},
+	}
+
+	// without the output image on the BuildRun, the Build's output is the base for the labels
+	br := &buildv1alpha1.BuildRun{}
+	u.recordGitCommit(br)
+	g.Expect(br.GetAnnotations()).To(Equal(map[string]string{
+		sourceGitCommitAnnotation: sha,
+		sourceGitDirtyAnnotation:  "true",
+	}))
+	g.Expect(br.Spec.Output.Image).To(Equal("registry.local/app"))
+	g.Expect(br.Spec.Output.Labels).To(Equal(map[string]string{
+		"team":             "a",
+		imageRevisionLabel: sha,
+		imageDirtyLabel:    "true",
+	}))
+	g.Expect(u.buildOutput.Labels).To(HaveLen(1))
+
+	// the output image informed on the BuildRun takes precedence
+	br = &buildv1alpha1.BuildRun{Spec: buildv1alpha1.BuildRunSpec{
+		Output: &buildv1alpha1.Image{Image: "registry.local/other"},
+	}}
+	u.recordGitCommit(br)
+	g.Expect(br.Spec.Output.Image).To(Equal("registry.local/other"))
+	g.Expect(br.Spec.Output.Labels).To(HaveKeyWithValue(imageRevisionLabel, sha))
+
+	// the working directory changes are not recorded for other commits
+	u.gitCommit = &streamer.GitCommit{SHA: sha}
+	br = &buildv1alpha1.BuildRun{}
+	u.recordGitCommit(br)
+	g.Expect(br.GetAnnotations()).To(Equal(map[string]string{sourceGitCommitAnnotation: sha}))
+	g.Expect(br.Spec.Output.Labels).To(HaveKeyWithValue(imageRevisionLabel, sha))
+	g.Expect(br.Spec.Output.Labels).ToNot(HaveKey(imageDirtyLabel))
+
+	// without a git ref nothing is recorded
+	br = &buildv1alpha1.BuildRun{}
+	(&UploadCommand{}).recordGitCommit(br)
+	g.Expect(br.GetAnnotations()).To(BeEmpty())
+	g.Expect(br.Spec.Output).To(BeNil())
+}
+
+func TestUploadCleanupBuildRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		uploadIsDone  bool
+		keepOnFailure bool
+		deleted       bool
+	}{
+		{name: "upload not completed", deleted: true},
+		{name: "upload completed", uploadIsDone: true},
+		{name: "keep on failure", keepOnFailure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			br := &buildv1alpha1.BuildRun{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "buildrun"},
+			}
+			clientset := shpfake.NewSimpleClientset(br)
+			u := &UploadCommand{
+				shpClientset:      clientset,
+				buildRunName:      br.GetName(),
+				buildRunNamespace: br.GetNamespace(),
+				uploadIsDone:      tt.uploadIsDone,
+				keepOnFailure:     tt.keepOnFailure,
+			}
+			u.cleanupBuildRun()
+
+			_, err := clientset.ShipwrightV1alpha1().BuildRuns(br.GetNamespace()).
+				Get(context.TODO(), br.GetName(), metav1.GetOptions{})
+			if tt.deleted {
+				g.Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+
+			// the timeout is only an error while the upload is not completed
+			u.onPodTimeout("request timeout has expired")
+			if tt.uploadIsDone {
+				g.Expect(u.podErr).To(BeNil())
+			} else {
+				g.Expect(u.podErr).To(MatchError(ContainSubstring("request timeout has expired")))
+			}
+		})
+	}
+}
+
+func TestUploadStartTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	// the BuildRun is created, but its build pod never shows up
+	shpClientset := shpfake.NewSimpleClientset()
+	shpClientset.PrependReactor("create", "buildruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		br := action.(k8stesting.CreateAction).GetObject().(*buildv1alpha1.BuildRun)
+		br.SetName(br.GetGenerateName() + "abcde")
+		return false, nil, nil
+	})
+	clientset := fake.NewSimpleClientset()
+	p := params.NewParamsForTest(clientset, shpClientset, nil, metav1.NamespaceDefault)
+	pw, err := reactor.NewPodWatcher(context.TODO(), math.MaxInt64, clientset, metav1.NamespaceDefault)
+	g.Expect(err).To(BeNil())
+
+	buildName := "build"
+	u := &UploadCommand{
+		ctx:          context.TODO(),
+		buildRefName: buildName,
+		buildRunSpec: &buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: buildName}},
+		shpClientset: shpClientset,
+		pw:           pw,
+		startTimeout: 100 * time.Millisecond,
+	}
+	out := &bytes.Buffer{}
+	err = u.runUpload(p, &genericclioptions.IOStreams{In: out, Out: out, ErrOut: out})
+	g.Expect(err).To(MatchError("build pod has not received the data upload within 100ms"))
+
+	// the BuildRun waiting for the data is deleted
+	brs, err := shpClientset.ShipwrightV1alpha1().BuildRuns(metav1.NamespaceDefault).
+		List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(brs.Items).To(BeEmpty())
+}
+
+func TestUploadBundleOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	// validating the combinations of registry credentials informed
+	for _, u := range []*UploadCommand{
+		{sourceDir: t.TempDir(), bundleCredentialsSecret: "secret", registryConfig: "config.json"},
+		{sourceDir: t.TempDir(), registryUsername: "user"},
+		{sourceDir: t.TempDir(), registryPasswordStdin: true},
+	} {
+		g.Expect(u.Validate()).ToNot(Succeed())
+	}
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "registry"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.local":{"username":"user","password":"pass"}}}`),
+		},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "opaque"},
+		Type:       corev1.SecretTypeOpaque,
+	})
+	p := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(), nil, "default")
+	ioStreams := &genericclioptions.IOStreams{In: strings.NewReader("password\n")}
+
+	u := &UploadCommand{
+		cmd:                     &cobra.Command{},
+		sourceBundleImage:       "registry.local/bundle",
+		bundleCredentialsSecret: "registry",
+		insecureRegistry:        true,
+	}
+	g.Expect(u.resolveBundleOptions(p, ioStreams)).To(Succeed())
+	g.Expect(u.bundleOpts.Insecure).To(BeTrue())
+	g.Expect(u.bundleOpts.Keychain).ToNot(BeNil())
+
+	u.bundleCredentialsSecret = "opaque"
+	g.Expect(u.resolveBundleOptions(p, ioStreams)).To(MatchError(ContainSubstring("is not of type")))
+
+	u = &UploadCommand{
+		cmd:                   &cobra.Command{},
+		sourceBundleImage:     "registry.local/bundle",
+		registryUsername:      "user",
+		registryPasswordStdin: true,
+	}
+	g.Expect(u.resolveBundleOptions(p, ioStreams)).To(Succeed())
+	g.Expect(u.bundleOpts.Keychain).ToNot(BeNil())
+
+	// registry options without a source bundle are rejected
+	u = &UploadCommand{cmd: &cobra.Command{}, buildRefName: "build", insecureRegistry: true}
+	g.Expect(u.resolveBundleOptions(p, ioStreams)).To(MatchError(ContainSubstring("only apply to Builds using a source bundle")))
+}
+
+func TestUploadCreateBuildRunPinsBundle(t *testing.T) {
+	g := NewWithT(t)
+
+	shpClientset := shpfake.NewSimpleClientset()
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpClientset, nil, "default")
+
+	digest, err := name.NewDigest("registry.local/source/bundle@sha256:" + strings.Repeat("a", 64))
+	g.Expect(err).To(BeNil())
+	buildName := "build"
+	u := &UploadCommand{
+		ctx:               context.TODO(),
+		buildRefName:      buildName,
+		buildRunSpec:      &buildv1alpha1.BuildRunSpec{BuildRef: &buildv1alpha1.BuildRef{Name: buildName}},
+		sourceBundleImage: "registry.local/source/bundle:latest",
+		bundleDigest:      digest,
+		buildSpec: &buildv1alpha1.BuildSpec{
+			Source: buildv1alpha1.Source{
+				BundleContainer: &buildv1alpha1.BundleContainer{Image: "registry.local/source/bundle:latest"},
+			},
+			Output: buildv1alpha1.Image{Image: "registry.local/app"},
+		},
+	}
+
+	br, err := u.createBuildRun(p)
+	g.Expect(err).To(BeNil())
+	g.Expect(br.Spec.BuildRef).To(BeNil())
+	g.Expect(br.Spec.BuildSpec).ToNot(BeNil())
+	g.Expect(br.Spec.BuildSpec.Source.BundleContainer.Image).To(Equal(digest.String()))
+	g.Expect(br.Spec.BuildSpec.Output.Image).To(Equal("registry.local/app"))
+	g.Expect(br.GetAnnotations()).To(HaveKeyWithValue(sourceBundleDigestAnnotation, digest.String()))
+	g.Expect(br.GetLabels()).To(HaveKeyWithValue(buildv1alpha1.LabelBuild, buildName))
+
+	// the Build specification informed is not modified
+	g.Expect(u.buildSpec.Source.BundleContainer.Image).To(Equal("registry.local/source/bundle:latest"))
+	g.Expect(u.buildRunSpec.BuildRef.Name).To(Equal(buildName))
+}
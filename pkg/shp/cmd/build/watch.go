@@ -0,0 +1,205 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// watchDebounce quiet interval after the last change on the local files before uploading again.
+var watchDebounce = 500 * time.Millisecond
+
+// resetUpload prepares the command for a new upload, with its own context, pod-watcher and
+// follower instances.
+func (u *UploadCommand) resetUpload(ctx context.Context, p *params.Params) error {
+	u.uploadLock.Lock()
+	u.uploadIsDone, u.podErr, u.buildRunNamespace, u.buildRunName = false, nil, "", ""
+	u.uploadLock.Unlock()
+	u.follower, u.ctx = nil, ctx
+
+	p.ResetWatchers()
+	var err error
+	u.pw, err = p.NewPodWatcher(ctx)
+	return err
+}
+
+// cancelBuildRun cancels the BuildRun of the current upload, when it's still running. Returns true
+// when the BuildRun is canceled, a BuildRun already removed by the upload cleanup is not an error.
+func (u *UploadCommand) cancelBuildRun() (bool, error) {
+	if u.buildRunName == "" {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	brClient := u.shpClientset.ShipwrightV1alpha1().BuildRuns(u.buildRunNamespace)
+	br, err := brClient.Get(ctx, u.buildRunName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil || br.IsDone() {
+		return false, err
+	}
+	patch, err := json.Marshal([]map[string]string{{
+		"op":    "replace",
+		"path":  "/spec/state",
+		"value": buildv1alpha1.BuildRunStateCancel,
+	}})
+	if err != nil {
+		return false, err
+	}
+	if _, err = brClient.Patch(ctx, u.buildRunName, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// uploadSummary returns the one-line summary of the upload, with the BuildRun outcome, the duration
+// and the output image digest.
+func uploadSummary(br *buildv1alpha1.BuildRun, elapsed time.Duration, canceled bool, err error) string {
+	elapsed = elapsed.Round(time.Second)
+	switch {
+	case br == nil && err != nil:
+		return fmt.Sprintf("Upload failed after %s: %v", elapsed, err)
+	case br == nil:
+		return fmt.Sprintf("Upload stopped after %s", elapsed)
+	case canceled:
+		return fmt.Sprintf("BuildRun '%s' canceled after %s, newer changes found", br.GetName(), elapsed)
+	}
+
+	condition := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	switch {
+	case condition != nil && condition.Status == corev1.ConditionTrue:
+		digest := "unknown"
+		if br.Status.Output != nil && br.Status.Output.Digest != "" {
+			digest = br.Status.Output.Digest
+		}
+		return fmt.Sprintf("BuildRun '%s' succeeded after %s, image digest %s", br.GetName(), elapsed, digest)
+	case condition != nil && condition.Status == corev1.ConditionFalse:
+		return fmt.Sprintf("BuildRun '%s' failed after %s: %s", br.GetName(), elapsed, condition.Reason)
+	case err != nil:
+		return fmt.Sprintf("BuildRun '%s' failed after %s: %v", br.GetName(), elapsed, err)
+	default:
+		return fmt.Sprintf("BuildRun '%s' stopped after %s", br.GetName(), elapsed)
+	}
+}
+
+// finalBuildRun returns the BuildRun of the current upload, when wait is informed it's watched until
+// it reaches a final state, bounded by the cleanup timeout. Returns nil when the BuildRun is not
+// found anymore.
+func (u *UploadCommand) finalBuildRun(wait bool) *buildv1alpha1.BuildRun {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	if wait {
+		var final *buildv1alpha1.BuildRun
+		keep := func(br *buildv1alpha1.BuildRun) error {
+			final = br
+			return nil
+		}
+		bw := reactor.NewBuildRunWatcher(ctx, u.shpClientset, u.buildRunNamespace, u.buildRunName).
+			WithOnSucceededFn(keep).
+			WithOnFailedFn(keep).
+			WithOnCanceledFn(keep)
+		if err := bw.Start(); err == nil && final != nil {
+			return final
+		}
+	}
+
+	br, err := u.shpClientset.ShipwrightV1alpha1().
+		BuildRuns(u.buildRunNamespace).
+		Get(ctx, u.buildRunName, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		return nil
+	case err != nil || br.GetName() == "":
+		return &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: u.buildRunName}}
+	}
+	return br
+}
+
+// printUploadSummary prints the summary of the current upload, with the BuildRun final state when
+// wait is informed.
+func (u *UploadCommand) printUploadSummary(w io.Writer, start time.Time, canceled bool, wait bool, err error) {
+	var br *buildv1alpha1.BuildRun
+	if u.buildRunName != "" {
+		br = u.finalBuildRun(wait)
+	}
+	fmt.Fprintln(w, uploadSummary(br, time.Since(start), canceled, err))
+}
+
+// watchUpload watches over the local files, uploading them for a new BuildRun whenever they change.
+// A BuildRun still running when newer changes are found is canceled.
+func (u *UploadCommand) watchUpload(p *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	tarball, err := u.newTar()
+	if err != nil {
+		return err
+	}
+	watcher, err := streamer.NewWatcher(tarball, watchDebounce)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	u.follow = true
+	for {
+		ctx, cancel := context.WithCancel(u.cmd.Context())
+		if err = u.resetUpload(ctx, p); err != nil {
+			cancel()
+			return err
+		}
+
+		start := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			done <- u.runUpload(p, ioStreams)
+		}()
+
+		interrupted, canceled := false, false
+		select {
+		case err = <-done:
+		case <-watcher.Changes():
+			log.Printf("Changes found on '%s', canceling the current upload...", u.sourceDir)
+			interrupted = true
+			cancel()
+			// the upload error is a consequence of the interruption
+			<-done
+			if canceled, err = u.cancelBuildRun(); err != nil {
+				log.Printf("Unable to cancel BuildRun '%s': %v", u.buildRunName, err)
+				err = nil
+			}
+		}
+		cancel()
+		// the BuildRun is only expected to reach a final state when the build pod is done
+		wait := !interrupted && u.cmd.Context().Err() == nil
+		u.printUploadSummary(ioStreams.Out, start, canceled, wait, err)
+
+		if !interrupted {
+			log.Printf("Watching '%s' for changes, press Ctrl-C to stop...", u.sourceDir)
+			select {
+			case <-watcher.Changes():
+			case <-u.cmd.Context().Done():
+				return nil
+			}
+		}
+		log.Printf("Changes found on '%s', uploading again...", u.sourceDir)
+	}
+}
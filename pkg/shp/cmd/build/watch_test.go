@@ -0,0 +1,149 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUploadSummary(t *testing.T) {
+	buildRun := func(status corev1.ConditionStatus, reason string, digest string) *buildv1alpha1.BuildRun {
+		br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Name: "br"}}
+		if status != "" {
+			br.Status.SetCondition(&buildv1alpha1.Condition{
+				Type:   buildv1alpha1.Succeeded,
+				Status: status,
+				Reason: reason,
+			})
+		}
+		if digest != "" {
+			br.Status.Output = &buildv1alpha1.Output{Digest: digest}
+		}
+		return br
+	}
+	elapsed := 61*time.Second + 400*time.Millisecond
+
+	tests := []struct {
+		name     string
+		br       *buildv1alpha1.BuildRun
+		canceled bool
+		err      error
+		summary  string
+	}{{
+		name:    "succeeded",
+		br:      buildRun(corev1.ConditionTrue, "Succeeded", "sha256:abc"),
+		summary: "BuildRun 'br' succeeded after 1m1s, image digest sha256:abc",
+	}, {
+		name:    "succeeded without digest",
+		br:      buildRun(corev1.ConditionTrue, "Succeeded", ""),
+		summary: "BuildRun 'br' succeeded after 1m1s, image digest unknown",
+	}, {
+		name:    "failed",
+		br:      buildRun(corev1.ConditionFalse, "Failed", ""),
+		err:     errors.New("build pod 'pod' has failed"),
+		summary: "BuildRun 'br' failed after 1m1s: Failed",
+	}, {
+		name:    "failed uploading",
+		br:      buildRun("", "", ""),
+		err:     errors.New("unable to extract"),
+		summary: "BuildRun 'br' failed after 1m1s: unable to extract",
+	}, {
+		name:     "canceled",
+		br:       buildRun(corev1.ConditionUnknown, "Running", ""),
+		canceled: true,
+		summary:  "BuildRun 'br' canceled after 1m1s, newer changes found",
+	}, {
+		name:    "no BuildRun",
+		err:     errors.New("max size exceeded"),
+		summary: "Upload failed after 1m1s: max size exceeded",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(uploadSummary(tt.br, elapsed, tt.canceled, tt.err)).To(Equal(tt.summary))
+		})
+	}
+}
+
+func TestCancelBuildRun(t *testing.T) {
+	running := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running"}}
+	done := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "done"}}
+	done.Status.SetCondition(&buildv1alpha1.Condition{
+		Type:   buildv1alpha1.Succeeded,
+		Status: corev1.ConditionTrue,
+	})
+
+	tests := []struct {
+		name     string
+		canceled bool
+	}{
+		{name: "running", canceled: true},
+		{name: "done"},
+		{name: "removed by the upload cleanup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clientset := shpfake.NewSimpleClientset(running.DeepCopy(), done.DeepCopy())
+			u := &UploadCommand{shpClientset: clientset, buildRunNamespace: "default", buildRunName: tt.name}
+
+			canceled, err := u.cancelBuildRun()
+			g.Expect(err).To(BeNil())
+			g.Expect(canceled).To(Equal(tt.canceled))
+			if !tt.canceled {
+				return
+			}
+			br, err := clientset.ShipwrightV1alpha1().BuildRuns("default").
+				Get(context.TODO(), tt.name, metav1.GetOptions{})
+			g.Expect(err).To(BeNil())
+			g.Expect(br.Spec.State).ToNot(BeNil())
+			g.Expect(*br.Spec.State).To(Equal(buildv1alpha1.BuildRunRequestedState(buildv1alpha1.BuildRunStateCancel)))
+		})
+	}
+}
+
+func TestPrintUploadSummaryWaitsFinalState(t *testing.T) {
+	g := NewWithT(t)
+
+	br := &buildv1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "br"}}
+	clientset := shpfake.NewSimpleClientset(br.DeepCopy())
+	u := &UploadCommand{shpClientset: clientset, buildRunNamespace: "default", buildRunName: "br"}
+
+	// the BuildRun reaches the final state, with the image digest, after the build pod is done
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		br.Status.SetCondition(&buildv1alpha1.Condition{
+			Type:   buildv1alpha1.Succeeded,
+			Status: corev1.ConditionTrue,
+		})
+		br.Status.Output = &buildv1alpha1.Output{Digest: "sha256:abc"}
+		_, _ = clientset.ShipwrightV1alpha1().BuildRuns("default").
+			UpdateStatus(context.TODO(), br, metav1.UpdateOptions{})
+	}()
+
+	out := &bytes.Buffer{}
+	u.printUploadSummary(out, time.Now(), false, true, nil)
+	g.Expect(out.String()).To(Equal("BuildRun 'br' succeeded after 0s, image digest sha256:abc\n"))
+
+	// a BuildRun removed by the upload cleanup is not reported as canceled
+	g.Expect(clientset.ShipwrightV1alpha1().BuildRuns("default").
+		Delete(context.TODO(), "br", metav1.DeleteOptions{})).To(Succeed())
+	canceled, err := u.cancelBuildRun()
+	g.Expect(err).To(BeNil())
+	out.Reset()
+	u.printUploadSummary(out, time.Now(), canceled, false, nil)
+	g.Expect(out.String()).To(Equal("Upload stopped after 0s\n"))
+}
@@ -0,0 +1,161 @@
+package buildrun
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/reactor"
+)
+
+// DescribeCommand contains data input from user for describe sub-command
+type DescribeCommand struct {
+	cmd *cobra.Command
+
+	name string
+}
+
+func describeCmd() runner.SubCommand {
+	return &DescribeCommand{
+		cmd: &cobra.Command{
+			Use:   "describe <name>",
+			Short: "Show details of a BuildRun, including its Kubernetes Events",
+			Args:  cobra.ExactArgs(1),
+		},
+	}
+}
+
+// Cmd returns cobra command object
+func (c *DescribeCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *DescribeCommand) Complete(params *params.Params, io *genericclioptions.IOStreams, args []string) error {
+	c.name = args[0]
+
+	return nil
+}
+
+// Validate validates data input by user
+func (c *DescribeCommand) Validate() error {
+	return nil
+}
+
+// timestamp formats the informed time, or "<none>" when not set.
+func timestamp(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Run executes describe sub-command logic
+func (c *DescribeCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	shpClientset, err := params.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	clientset, err := params.ClientSet()
+	if err != nil {
+		return err
+	}
+
+	br, err := shpClientset.ShipwrightV1alpha1().BuildRuns(params.Namespace()).Get(c.cmd.Context(), c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// objects involved in the build, their events are shown as well
+	involved := []corev1.ObjectReference{{Kind: "BuildRun", Name: br.Name}}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(writer, "Name:\t%s\n", br.Name)
+	fmt.Fprintf(writer, "Namespace:\t%s\n", br.Namespace)
+	if br.Spec.BuildRef != nil {
+		fmt.Fprintf(writer, "Build:\t%s\n", br.Spec.BuildRef.Name)
+	}
+	status, reason, message := string(metav1.ConditionUnknown), "", ""
+	if c := br.Status.GetCondition(buildv1alpha1.Succeeded); c != nil {
+		status, reason, message = string(c.Status), c.Reason, c.Message
+	}
+	fmt.Fprintf(writer, "Succeeded:\t%s\n", status)
+	fmt.Fprintf(writer, "Reason:\t%s\n", reason)
+	fmt.Fprintf(writer, "Message:\t%s\n", message)
+	fmt.Fprintf(writer, "Started:\t%s\n", timestamp(br.Status.StartTime))
+	fmt.Fprintf(writer, "Completed:\t%s\n", timestamp(br.Status.CompletionTime))
+	if br.Status.LatestTaskRunRef != nil {
+		fmt.Fprintf(writer, "TaskRun:\t%s\n", *br.Status.LatestTaskRunRef)
+		involved = append(involved, corev1.ObjectReference{Kind: "TaskRun", Name: *br.Status.LatestTaskRunRef})
+	}
+
+	pods, err := clientset.CoreV1().Pods(params.Namespace()).List(c.cmd.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", buildv1alpha1.LabelBuildRun, br.Name),
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		fmt.Fprintf(writer, "Pod:\t%s (%s)\n", pod.Name, pod.Status.Phase)
+		involved = append(involved, corev1.ObjectReference{Kind: "Pod", Name: pod.Name, UID: pod.UID})
+	}
+	writer.Flush()
+
+	// the events of each object involved are selected by the API server
+	items := []corev1.Event{}
+	for _, obj := range involved {
+		selector := fields.Set{"involvedObject.kind": obj.Kind, "involvedObject.name": obj.Name}
+		if obj.UID != "" {
+			selector["involvedObject.uid"] = string(obj.UID)
+		}
+		events, err := clientset.CoreV1().Events(params.Namespace()).List(c.cmd.Context(), metav1.ListOptions{
+			FieldSelector: selector.String(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, event := range events.Items {
+			if event.InvolvedObject.Kind == obj.Kind && event.InvolvedObject.Name == obj.Name &&
+				(obj.UID == "" || event.InvolvedObject.UID == obj.UID) {
+				items = append(items, event)
+			}
+		}
+	}
+	reactor.SortEvents(items)
+
+	fmt.Fprintln(ioStreams.Out, "\nEvents:")
+	if len(items) == 0 {
+		fmt.Fprintln(ioStreams.Out, "  <none>")
+		return nil
+	}
+	writer = tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "  LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for _, event := range items {
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.CreationTimestamp.Time
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s/%s\t%s\n",
+			duration.ShortHumanDuration(time.Since(lastSeen)),
+			event.Type,
+			event.Reason,
+			strings.ToLower(event.InvolvedObject.Kind),
+			event.InvolvedObject.Name,
+			strings.TrimSpace(event.Message),
+		)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,68 @@
+package buildrun
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+func TestDescribeBuildRun(t *testing.T) {
+	taskRun := "br-tr"
+	br := &v1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+		Spec:       v1alpha1.BuildRunSpec{BuildRef: &v1alpha1.BuildRef{Name: "build"}},
+		Status: v1alpha1.BuildRunStatus{
+			LatestTaskRunRef: &taskRun,
+			Conditions: v1alpha1.Conditions{{
+				Type:   v1alpha1.Succeeded,
+				Status: corev1.ConditionFalse,
+				Reason: "PodCreationFailed",
+			}},
+		},
+	}
+	event := func(name, kind, objName, reason string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objName},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        "message",
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		event("e1", "TaskRun", taskRun, "FailedCreate"),
+		event("e2", "BuildRun", "br", "Denied"),
+		event("e3", "Pod", "other-pod", "FailedScheduling"),
+	)
+	param := params.NewParamsForTest(clientset, shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := &DescribeCommand{cmd: &cobra.Command{}}
+	cmd.Cmd().ExecuteC()
+	if err := cmd.Complete(param, &ioStreams, []string{"br"}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	for _, expected := range []string{"PodCreationFailed", "taskrun/br-tr", "FailedCreate", "buildrun/br", "Denied"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q on output: %s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "FailedScheduling") {
+		t.Errorf("unexpected event on output: %s", out.String())
+	}
+}
@@ -10,36 +10,75 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	"github.com/spf13/cobra"
 
+	"github.com/shipwright-io/cli/pkg/shp/archive"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/follower"
 	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/flags"
 	"github.com/shipwright-io/cli/pkg/shp/params"
-	"github.com/shipwright-io/cli/pkg/shp/util"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 )
 
 // LogsCommand contains data input from user for logs sub-command
 type LogsCommand struct {
 	cmd *cobra.Command
 
-	name string
+	name       string   // buildrun name, when a single buildrun is informed
+	names      []string // buildrun names
+	selector   string   // buildrun label selector
+	buildName  string   // build name, selects its buildruns
+	allRunning bool     // only select buildruns which are not done yet
 
-	follow   bool
-	follower *follower.Follower
+	follow      bool
+	follower    *follower.Follower
+	group       *follower.Group // follows several buildruns at once
+	logFormat   tail.Format
+	colorMode   tail.ColorMode
+	timestamps  bool
+	archiveLogs bool
+	events      bool
+	saveTo      string
+	archives    []*archive.Archive
 }
 
+const logsLongDesc = `
+Shows the logs of one or more BuildRuns. BuildRuns are either informed by name, or selected by
+label selector, or by the Build they belong to. For example:
+
+	$ shp buildrun logs -F my-app-run-1 my-app-run-2
+	$ shp buildrun logs -F --selector team=payments
+	$ shp buildrun logs -F --build my-app --all-running
+
+When more than one BuildRun is followed, the log lines are prefixed by the BuildRun and step names,
+and the command fails when any of the BuildRuns fails.
+`
+
 func logsCmd() runner.SubCommand {
 	cmd := &cobra.Command{
-		Use:   "logs <name>",
+		Use:   "logs [<name>...]",
 		Short: "See BuildRun log output",
-		Args:  cobra.ExactArgs(1),
+		Long:  logsLongDesc,
+		Args:  cobra.ArbitraryArgs,
 	}
 	logCommand := &LogsCommand{
-		cmd: cmd,
+		cmd:       cmd,
+		logFormat: tail.FormatText,
+		colorMode: tail.ColorAuto,
 	}
 	cmd.Flags().BoolVarP(&logCommand.follow, "follow", "F", logCommand.follow, "Follow the log of a buildrun until it completes or fails.")
+	flags.LogFormatFlag(cmd.Flags(), &logCommand.logFormat)
+	flags.ColorFlag(cmd.Flags(), &logCommand.colorMode)
+	flags.TimestampsFlag(cmd.Flags(), &logCommand.timestamps)
+	flags.ArchiveLogsFlag(cmd.Flags(), &logCommand.archiveLogs)
+	flags.SaveToFlag(cmd.Flags(), &logCommand.saveTo)
+	flags.EventsFlag(cmd.Flags(), &logCommand.events)
+	cmd.Flags().StringVarP(&logCommand.selector, "selector", "l", logCommand.selector, "Label selector to select the BuildRuns.")
+	cmd.Flags().StringVar(&logCommand.buildName, "build", logCommand.buildName, "Select the BuildRuns of the informed Build.")
+	cmd.Flags().BoolVar(&logCommand.allRunning, "all-running", logCommand.allRunning, "Only select the BuildRuns which have not completed yet.")
 	return logCommand
 }
 
@@ -50,18 +89,106 @@ func (c *LogsCommand) Cmd() *cobra.Command {
 
 // Complete fills in data provided by user
 func (c *LogsCommand) Complete(params *params.Params, ioStreams *genericclioptions.IOStreams, args []string) error {
-	c.name = args[0]
+	c.names = args
+	if err := c.selectBuildRuns(params); err != nil {
+		return err
+	}
+	if len(c.names) == 1 {
+		c.name = c.names[0]
+	}
+
+	var err error
+	if c.archives, err = archive.Targets(c.archiveLogs, c.saveTo); err != nil {
+		return err
+	}
 	if !c.follow {
 		return nil
 	}
 
+	if len(c.names) > 1 {
+		brs := []types.NamespacedName{}
+		for _, name := range c.names {
+			brs = append(brs, types.NamespacedName{Namespace: params.Namespace(), Name: name})
+		}
+		if c.group, err = params.NewFollowerGroup(c.Cmd().Context(), brs, ioStreams); err != nil {
+			return err
+		}
+		for _, f := range c.group.Followers() {
+			if err = c.setupFollower(f, ioStreams); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	br := types.NamespacedName{
 		Namespace: params.Namespace(),
 		Name:      c.name,
 	}
-	var err error
-	c.follower, err = params.NewFollower(c.Cmd().Context(), br, ioStreams)
-	return err
+	if c.follower, err = params.NewFollower(c.Cmd().Context(), br, ioStreams); err != nil {
+		return err
+	}
+	return c.setupFollower(c.follower, ioStreams)
+}
+
+// setupFollower configures the follower with the log output flags.
+func (c *LogsCommand) setupFollower(f *follower.Follower, ioStreams *genericclioptions.IOStreams) error {
+	if err := f.SetLogFormat(c.logFormat); err != nil {
+		return err
+	}
+	f.SetColor(tail.ColorEnabled(c.colorMode, ioStreams.Out))
+	f.SetTimestamps(c.timestamps)
+	f.SetEvents(c.events)
+	for _, a := range c.archives {
+		f.WithArchive(a)
+	}
+	return nil
+}
+
+// selectBuildRuns lists the BuildRuns matching the label selector, or the Build name, when
+// informed, instead of BuildRun names.
+func (c *LogsCommand) selectBuildRuns(p *params.Params) error {
+	if c.selector == "" && c.buildName == "" {
+		if c.allRunning {
+			return fmt.Errorf("--all-running requires either --selector or --build")
+		}
+		if len(c.names) == 0 {
+			return fmt.Errorf("either BuildRun names, --selector or --build must be informed")
+		}
+		return nil
+	}
+	if len(c.names) > 0 {
+		return fmt.Errorf("BuildRun names can not be combined with --selector or --build")
+	}
+
+	selector := []string{}
+	if c.selector != "" {
+		selector = append(selector, c.selector)
+	}
+	if c.buildName != "" {
+		selector = append(selector, fmt.Sprintf("%s=%s", buildv1alpha1.LabelBuild, c.buildName))
+	}
+
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return err
+	}
+	brs, err := clientset.ShipwrightV1alpha1().BuildRuns(p.Namespace()).List(c.cmd.Context(), v1.ListOptions{
+		LabelSelector: strings.Join(selector, ","),
+	})
+	if err != nil {
+		return err
+	}
+	for _, br := range brs.Items {
+		if c.allRunning && br.IsDone() {
+			continue
+		}
+		c.names = append(c.names, br.GetName())
+	}
+	if len(c.names) == 0 {
+		return fmt.Errorf("no BuildRuns found matching %q", strings.Join(selector, ","))
+	}
+	return nil
 }
 
 // Validate validates data input by user
@@ -69,8 +196,93 @@ func (c *LogsCommand) Validate() error {
 	return nil
 }
 
+// newTail instantiate a Tail using the informed log format, storing logs on the informed archives.
+func (c *LogsCommand) newTail(clientset kubernetes.Interface, ioStreams *genericclioptions.IOStreams, archives []*archive.Archive) (*tail.Tail, error) {
+	formatter, err := tail.NewFormatter(c.logFormat)
+	if err != nil {
+		return nil, err
+	}
+	logTail := tail.NewTail(c.cmd.Context(), clientset)
+	logTail.SetStdout(ioStreams.Out)
+	logTail.SetStderr(ioStreams.ErrOut)
+	logTail.SetFormatter(formatter)
+	logTail.SetBuildRun(c.name)
+	for _, a := range archives {
+		logTail.WithArchive(a)
+	}
+	return logTail, nil
+}
+
+// dumpLogs writes the logs of all pod containers using the informed log format.
+func (c *LogsCommand) dumpLogs(clientset kubernetes.Interface, pod *corev1.Pod, ioStreams *genericclioptions.IOStreams) error {
+	logTail, err := c.newTail(clientset, ioStreams, c.archives)
+	if err != nil {
+		return err
+	}
+
+	logTail.Message(fmt.Sprintf("Obtaining logs for BuildRun %q\n", c.name))
+	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
+	for _, container := range containers {
+		if err := logTail.Dump(pod.GetNamespace(), pod.GetName(), container.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archivedContainers lists the containers stored on the local archive for the BuildRun.
+func (c *LogsCommand) archivedContainers(ns string) []archive.Container {
+	cache, err := archive.NewDefaultArchive()
+	if err != nil {
+		return nil
+	}
+	containers, err := cache.Containers(ns, c.name)
+	if err != nil {
+		return nil
+	}
+	return containers
+}
+
+// replayLogs writes the logs stored on the local archive, employed when the builder pod is gone.
+// Replayed logs are only copied to the "save-to" directory, when informed.
+func (c *LogsCommand) replayLogs(clientset kubernetes.Interface, ns string, containers []archive.Container, ioStreams *genericclioptions.IOStreams) error {
+	archives := []*archive.Archive{}
+	if c.saveTo != "" {
+		archives = append(archives, archive.NewArchive(c.saveTo))
+	}
+	logTail, err := c.newTail(clientset, ioStreams, archives)
+	if err != nil {
+		return err
+	}
+
+	logTail.Message(fmt.Sprintf("Builder pod for BuildRun %q is gone, obtaining logs from the local archive\n", c.name))
+	for _, container := range containers {
+		if err := logTail.Replay(ns, container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Run executes logs sub-command logic
 func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	if c.group != nil {
+		return c.group.Start()
+	}
+	if len(c.names) <= 1 {
+		return c.logs(params, ioStreams)
+	}
+	for _, name := range c.names {
+		c.name = name
+		if err := c.logs(params, ioStreams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logs shows the logs of a single BuildRun.
+func (c *LogsCommand) logs(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
 	clientset, err := params.ClientSet()
 	if err != nil {
 		return err
@@ -85,12 +297,22 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 	// is invoked.
 	justGetLogs := false
 	var pods *corev1.PodList
+	var archived []archive.Container
 	err = wait.PollImmediate(1*time.Second, 10*time.Second, func() (done bool, err error) {
 		if pods, err = clientset.CoreV1().Pods(params.Namespace()).List(c.cmd.Context(), lo); err != nil {
 			fmt.Fprintf(ioStreams.ErrOut, "error listing Pods for BuildRun %q: %s\n", c.name, err.Error())
 			return false, nil
 		}
 		if len(pods.Items) == 0 {
+			// logs are only archived once the pod exists, there is no reason to keep waiting when the
+			// local archive already holds logs for the BuildRun
+			if archived = c.archivedContainers(params.Namespace()); len(archived) > 0 {
+				return true, nil
+			}
+			// the follower waits for the pod, and handles BuildRuns which never create one
+			if c.follow {
+				return true, nil
+			}
 			fmt.Fprintf(ioStreams.ErrOut, "no builder pod found for BuildRun %q\n", c.name)
 			return false, nil
 		}
@@ -99,19 +321,35 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 	if err != nil {
 		return err
 	}
+	if len(pods.Items) == 0 {
+		if len(archived) > 0 {
+			return c.replayLogs(clientset, params.Namespace(), archived, ioStreams)
+		}
+		_, err = c.follower.Start(lo)
+		return err
+	}
 	pod := pods.Items[0]
 	phase := pod.Status.Phase
 	if phase == corev1.PodFailed || phase == corev1.PodSucceeded {
 		justGetLogs = true
 	}
 
+	if (!c.follow || justGetLogs) && c.logFormat != tail.FormatText {
+		return c.dumpLogs(clientset, &pod, ioStreams)
+	}
+
 	if !c.follow || justGetLogs {
 		fmt.Fprintf(ioStreams.Out, "Obtaining logs for BuildRun %q\n\n", c.name)
 
+		// the logs fetched are stored on the archives as well
+		logTail, err := c.newTail(clientset, ioStreams, c.archives)
+		if err != nil {
+			return err
+		}
 		var b strings.Builder
 		containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
 		for _, container := range containers {
-			logs, err := util.GetPodLogs(c.cmd.Context(), clientset, pod, container.Name)
+			logs, err := logTail.Fetch(pod.GetNamespace(), pod.GetName(), container.Name)
 			if err != nil {
 				return err
 			}
@@ -121,9 +359,7 @@ func (c *LogsCommand) Run(params *params.Params, ioStreams *genericclioptions.IO
 		}
 
 		fmt.Fprintln(ioStreams.Out, b.String())
-
 		return nil
-
 	}
 	_, err = c.follower.Start(lo)
 	return err
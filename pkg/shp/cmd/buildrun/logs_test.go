@@ -2,12 +2,18 @@ package buildrun
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	fakekubetesting "k8s.io/client-go/testing"
 
 	"github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
@@ -55,6 +61,90 @@ func TestStreamBuildLogs(t *testing.T) {
 
 }
 
+func TestStreamBuildLogsJSONFormat(t *testing.T) {
+	name := "test-obj"
+	pod := &corev1.Pod{}
+	pod.Name = name
+	pod.Namespace = metav1.NamespaceDefault
+	pod.Labels = map[string]string{
+		v1alpha1.LabelBuildRun: name,
+	}
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "step-build",
+		},
+	}
+
+	cmd := LogsCommand{cmd: &cobra.Command{}, logFormat: tail.FormatJSON}
+	cmd.name = name
+	// set up context
+	cmd.Cmd().ExecuteC()
+
+	clientset := fake.NewSimpleClientset(pod)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	param := params.NewParamsForTest(clientset, nil, nil, metav1.NamespaceDefault)
+	err := cmd.Run(param, &ioStreams)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if !strings.Contains(out.String(), `"step":"build","timestamp":`) ||
+		!strings.Contains(out.String(), `"line":"fake logs"`) {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func TestStreamBuildLogsArchive(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	saveTo := t.TempDir()
+
+	name := "test-obj"
+	pod := &corev1.Pod{}
+	pod.Name = name
+	pod.Namespace = metav1.NamespaceDefault
+	pod.Labels = map[string]string{
+		v1alpha1.LabelBuildRun: name,
+	}
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "step-build",
+		},
+	}
+
+	// fetching the logs while the pod exists, storing them on the local archive
+	cmd := LogsCommand{cmd: &cobra.Command{}, logFormat: tail.FormatText, archiveLogs: true}
+	cmd.Cmd().ExecuteC()
+
+	param := params.NewParamsForTest(fake.NewSimpleClientset(pod), nil, nil, metav1.NamespaceDefault)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+	if err := cmd.Complete(param, &ioStreams, []string{name}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// the pod is gone, logs are served from the local archive and exported
+	cmd = LogsCommand{cmd: &cobra.Command{}, logFormat: tail.FormatText, saveTo: saveTo}
+	cmd.Cmd().ExecuteC()
+
+	param = params.NewParamsForTest(fake.NewSimpleClientset(), nil, nil, metav1.NamespaceDefault)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+	if err := cmd.Complete(param, &ioStreams, []string{name}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if err := cmd.Run(param, &ioStreams); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if !strings.Contains(out.String(), "local archive") || !strings.Contains(out.String(), "[build] fake logs") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+
+	exported := filepath.Join(saveTo, metav1.NamespaceDefault, name, name, "step-build.log")
+	if _, err := os.Stat(exported); err != nil {
+		t.Fatalf("logs were not exported: %s", err.Error())
+	}
+}
+
 func TestStreamBuildRunFollowLogs(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -209,6 +299,14 @@ func TestStreamBuildRunFollowLogs(t *testing.T) {
 					Status: corev1.ConditionFalse,
 				},
 			}
+		case test.phase == corev1.PodFailed:
+			// the BuildRun is marked as failed together with its pod, the failure is reported then
+			br.Status.Conditions = []v1alpha1.Condition{
+				{
+					Type:   v1alpha1.Succeeded,
+					Status: corev1.ConditionFalse,
+				},
+			}
 		}
 
 		cmd.Complete(param, &ioStreams, []string{name})
@@ -239,10 +337,179 @@ func TestStreamBuildRunFollowLogs(t *testing.T) {
 }
 
 func checkLog(name, text string, cmd *LogsCommand, out *bytes.Buffer, t *testing.T) {
-	// need to employ log lock since accessing same iostream out used by Run cmd
-	cmd.follower.GetLogLock().Lock()
-	defer cmd.follower.GetLogLock().Unlock()
-	if !strings.Contains(out.String(), text) {
-		t.Errorf("test %s: unexpected output: %s", name, out.String())
+	// the outcome may be reported asynchronously, as when the BuildRun final state is observed
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// need to employ log lock since accessing same iostream out used by Run cmd
+		cmd.follower.GetLogLock().Lock()
+		output := out.String()
+		cmd.follower.GetLogLock().Unlock()
+		if strings.Contains(output, text) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("test %s: unexpected output: %s", name, output)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestStreamBuildRunFollowLogsGroup(t *testing.T) {
+	buildRun := func(name string, status corev1.ConditionStatus) *v1alpha1.BuildRun {
+		br := &v1alpha1.BuildRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{v1alpha1.LabelBuild: "build"},
+			},
+		}
+		if status != "" {
+			br.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Succeeded, Status: status}}
+		}
+		return br
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	shpclientset := shpfake.NewSimpleClientset(
+		buildRun("run-a", ""),
+		buildRun("run-b", ""),
+		buildRun("run-c", corev1.ConditionTrue),
+		buildRun("run-d", ""),
+	)
+	param := params.NewParamsForTest(fake.NewSimpleClientset(), shpclientset, genericclioptions.NewConfigFlags(true), metav1.NamespaceDefault)
+	ioStreams, _, _, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := &LogsCommand{cmd: &cobra.Command{}, follow: true, buildName: "build", allRunning: true}
+	cmd.Cmd().ExecuteC()
+	if err := cmd.Complete(param, &ioStreams, []string{}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	if strings.Join(cmd.names, ",") != "run-a,run-b,run-d" {
+		t.Fatalf("unexpected BuildRuns selected: %v", cmd.names)
+	}
+
+	// marking the BuildRuns as done, the follower reads their final state, a BuildRun whose pod has
+	// succeeded without reaching a successful condition is not considered succeeded
+	brClient := shpclientset.ShipwrightV1alpha1().BuildRuns(metav1.NamespaceDefault)
+	for _, br := range []*v1alpha1.BuildRun{
+		buildRun("run-a", corev1.ConditionTrue),
+		buildRun("run-b", corev1.ConditionFalse),
+		buildRun("run-d", corev1.ConditionUnknown),
+	} {
+		if _, err := brClient.Update(cmd.Cmd().Context(), br, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- cmd.Run(param, &ioStreams)
+	}()
+
+	followers := cmd.group.Followers()
+	followers[0].OnEvent(pod("run-a-pod", corev1.PodSucceeded))
+	followers[1].OnEvent(pod("run-b-pod", corev1.PodFailed))
+	followers[2].OnEvent(pod("run-d-pod", corev1.PodSucceeded))
+
+	err := <-errCh
+	if err == nil || err.Error() != "2 of 3 BuildRuns have failed: run-b, run-d" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStreamBuildRunFollowLogsWithoutPod(t *testing.T) {
+	br := &v1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "br",
+		},
+		Status: v1alpha1.BuildRunStatus{
+			Conditions: v1alpha1.Conditions{{
+				Type:    v1alpha1.Succeeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "BuildStrategyNotFound",
+				Message: "strategy not found",
+			}},
+		},
+	}
+	param := params.NewParamsForTest(
+		fake.NewSimpleClientset(),
+		shpfake.NewSimpleClientset(br),
+		genericclioptions.NewConfigFlags(true),
+		metav1.NamespaceDefault,
+	)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := &LogsCommand{cmd: &cobra.Command{}, follow: true}
+	cmd.Cmd().ExecuteC()
+	if err := cmd.Complete(param, &ioStreams, []string{br.Name}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	// the build pod is never created, the BuildRun failure is reported instead
+	err := cmd.Run(param, &ioStreams)
+	if err == nil {
+		t.Fatal("expected error, BuildRun has failed")
+	}
+	checkLog("without-pod", "BuildRun \"br\" has failed: BuildStrategyNotFound strategy not found", cmd, out, t)
+}
+
+func TestStreamBuildRunFollowLogsRetry(t *testing.T) {
+	name := "br"
+	attempt := func(uid string, created time.Time, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         metav1.NamespaceDefault,
+				Name:              fmt.Sprintf("%s-%s-pod", name, uid),
+				UID:               types.UID(uid),
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	br := &v1alpha1.BuildRun{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name}}
+	param := params.NewParamsForTest(
+		fake.NewSimpleClientset(),
+		shpfake.NewSimpleClientset(br),
+		genericclioptions.NewConfigFlags(true),
+		metav1.NamespaceDefault,
+	)
+	ioStreams, _, out, _ := genericclioptions.NewTestIOStreams()
+
+	cmd := &LogsCommand{cmd: &cobra.Command{}, follow: true}
+	cmd.Cmd().ExecuteC()
+	if err := cmd.Complete(param, &ioStreams, []string{name}); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	now := time.Now()
+	first := attempt("first", now.Add(-time.Minute), corev1.PodPending)
+	second := attempt("second", now, corev1.PodPending)
+
+	for _, pod := range []*corev1.Pod{first, second} {
+		if err := cmd.follower.OnEvent(pod); err != nil {
+			t.Fatalf("%s", err.Error())
+		}
+	}
+	checkLog("retry", "BuildRun \"br\" attempt 2, following the new pod \"br-second-pod\"", cmd, out, t)
+
+	// the first attempt has been replaced, its failure does not end the log following
+	first.Status.Phase = corev1.PodFailed
+	if err := cmd.follower.OnEvent(first); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	second.Status.Phase = corev1.PodSucceeded
+	if err := cmd.follower.OnEvent(second); err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+	checkLog("retry", "Pod \"br-second-pod\" has succeeded!", cmd, out, t)
+	if strings.Contains(out.String(), "br-first-pod\" has failed") {
+		t.Errorf("unexpected output: %s", out.String())
 	}
 }
@@ -0,0 +1,121 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/bundle"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// Command represents "shp bundle" sub-command.
+func Command(p *params.Params, ioStreams *genericclioptions.IOStreams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "bundle",
+		Short: "Inspect source bundle images",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	command.AddCommand(
+		runner.NewRunner(p, ioStreams, listFilesCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, pullCmd()).Cmd(),
+		runner.NewRunner(p, ioStreams, inspectCmd()).Cmd(),
+	)
+	return command
+}
+
+// imageOptions locates the source bundle image, informed directly or by the BuildRun that pulled
+// it, and holds the settings to access its registry.
+type imageOptions struct {
+	image            string // source bundle image reference
+	buildRunName     string // BuildRun to resolve the source bundle image and digest from
+	registryConfig   string // Docker configuration file with the registry credentials
+	insecureRegistry bool   // allows plain HTTP registries
+}
+
+// addFlags registers the flags to locate the source bundle image and access its registry.
+func (o *imageOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&o.buildRunName,
+		"buildrun",
+		o.buildRunName,
+		"BuildRun to resolve the source bundle image from, pinned to the digest on its status.",
+	)
+	flags.StringVar(
+		&o.registryConfig,
+		"registry-config",
+		o.registryConfig,
+		"Docker configuration file (config.json) with the registry credentials to pull the source bundle.",
+	)
+	flags.BoolVar(
+		&o.insecureRegistry,
+		"insecure-registry",
+		o.insecureRegistry,
+		"Pull the source bundle from a registry using plain HTTP.",
+	)
+}
+
+// complete takes the image from the first argument, unless the BuildRun is informed, returning the
+// remaining arguments, up to the amount the sub-command expects.
+func (o *imageOptions) complete(args []string, expected int) ([]string, error) {
+	if o.buildRunName == "" && len(args) > 0 {
+		o.image, args = args[0], args[1:]
+	}
+	if len(args) > expected {
+		return nil, fmt.Errorf("the source bundle image can not be informed together with the --buildrun flag")
+	}
+	return args, nil
+}
+
+// validate checks the image or the BuildRun is informed.
+func (o *imageOptions) validate() error {
+	if o.image == "" && o.buildRunName == "" {
+		return fmt.Errorf("either the source bundle image or the --buildrun flag must be informed")
+	}
+	return nil
+}
+
+// resolveImage returns the source bundle image informed, or the one the BuildRun has pulled.
+func (o *imageOptions) resolveImage(ctx context.Context, p *params.Params) (string, error) {
+	if o.buildRunName == "" {
+		return o.image, nil
+	}
+	clientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return "", err
+	}
+	br, err := clientset.ShipwrightV1alpha1().BuildRuns(p.Namespace()).Get(ctx, o.buildRunName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return bundle.GetSourceBundleDigest(ctx, clientset, br)
+}
+
+// fetch resolves and retrieves the source bundle image, returning its reference as well.
+func (o *imageOptions) fetch(ctx context.Context, p *params.Params) (string, v1.Image, error) {
+	image, err := o.resolveImage(ctx, p)
+	if err != nil {
+		return "", nil, err
+	}
+	opts := bundle.Options{Insecure: o.insecureRegistry}
+	if o.registryConfig != "" {
+		if opts.Keychain, err = bundle.NewDockerConfigFileKeychain(o.registryConfig); err != nil {
+			return "", nil, err
+		}
+	}
+	img, err := bundle.Fetch(ctx, image, opts)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to retrieve source bundle '%s': %w", image, err)
+	}
+	return image, img, nil
+}
@@ -0,0 +1,203 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/test/stub"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPullArguments(t *testing.T) {
+	tests := []struct {
+		name     string
+		buildRun string
+		args     []string
+		image    string
+		dir      string
+		err      string
+	}{{
+		name:  "image and directory",
+		args:  []string{"registry.local/source:latest", "src"},
+		image: "registry.local/source:latest",
+		dir:   "src",
+	}, {
+		name:     "BuildRun and directory",
+		buildRun: "br",
+		args:     []string{"src"},
+		dir:      "src",
+	}, {
+		name: "directory missing",
+		args: []string{"registry.local/source:latest"},
+		err:  "the target directory must be informed",
+	}, {
+		name:     "image and BuildRun",
+		buildRun: "br",
+		args:     []string{"registry.local/source:latest", "src"},
+		err:      "the source bundle image can not be informed together with the --buildrun flag",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cmd := pullCmd().(*PullCommand)
+			cmd.buildRunName = tt.buildRun
+
+			err := cmd.Complete(nil, nil, tt.args)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(cmd.Validate()).To(Succeed())
+			g.Expect(cmd.image).To(Equal(tt.image))
+			g.Expect(cmd.dir).To(Equal(tt.dir))
+		})
+	}
+
+	g := NewWithT(t)
+	cmd := inspectCmd().(*InspectCommand)
+	g.Expect(cmd.Complete(nil, nil, nil)).To(Succeed())
+	g.Expect(cmd.Validate()).To(MatchError(ContainSubstring("--buildrun")))
+}
+
+func TestResolveImageFromBuildRun(t *testing.T) {
+	g := NewWithT(t)
+
+	const digest = "sha256:4a5b1e9a3ef5b4c5e0b8a4b5c7b7f6b1f3e0f2d1b1e4b0e2f8a5c6e1a5b2d3c4"
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+		Spec: buildv1alpha1.BuildRunSpec{BuildSpec: &buildv1alpha1.BuildSpec{
+			Source: buildv1alpha1.Source{
+				BundleContainer: &buildv1alpha1.BundleContainer{Image: "registry.local/source:latest"},
+			},
+		}},
+		Status: buildv1alpha1.BuildRunStatus{Sources: []buildv1alpha1.SourceResult{{
+			Name:   "default",
+			Bundle: &buildv1alpha1.BundleSourceResult{Digest: digest},
+		}}},
+	}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(br), nil, metav1.NamespaceDefault)
+
+	opts := &imageOptions{buildRunName: "br"}
+	image, err := opts.resolveImage(context.TODO(), p)
+	g.Expect(err).To(BeNil())
+	g.Expect(image).To(Equal("registry.local/source@" + digest))
+
+	opts = &imageOptions{buildRunName: "missing"}
+	_, err = opts.resolveImage(context.TODO(), p)
+	g.Expect(err).To(MatchError(ContainSubstring("not found")))
+}
+
+// serveImage serves the image on a registry, as "source/bundle:latest".
+func serveImage(g *WithT, img v1.Image) *httptest.Server {
+	manifest, err := img.RawManifest()
+	g.Expect(err).To(BeNil())
+	mediaType, err := img.MediaType()
+	g.Expect(err).To(BeNil())
+	config, err := img.RawConfigFile()
+	g.Expect(err).To(BeNil())
+	configName, err := img.ConfigName()
+	g.Expect(err).To(BeNil())
+	blobs := map[string][]byte{configName.String(): config}
+	layers, err := img.Layers()
+	g.Expect(err).To(BeNil())
+	for _, layer := range layers {
+		digest, err := layer.Digest()
+		g.Expect(err).To(BeNil())
+		rc, err := layer.Compressed()
+		g.Expect(err).To(BeNil())
+		data, err := io.ReadAll(rc)
+		g.Expect(err).To(BeNil())
+		blobs[digest.String()] = data
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/source/bundle/manifests/latest":
+			w.Header().Set("Content-Type", string(mediaType))
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(r.URL.Path, "/v2/source/bundle/blobs/"):
+			data, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/source/bundle/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestPullRejectsEscapingEntries(t *testing.T) {
+	g := NewWithT(t)
+
+	img, err := stub.LayerImage(&tar.Header{
+		Typeflag: tar.TypeReg, Name: "../escape.txt", Mode: 0o644, Size: 1,
+	})
+	g.Expect(err).To(BeNil())
+	registry := serveImage(g, img)
+	defer registry.Close()
+
+	outside := t.TempDir()
+	dir := filepath.Join(outside, "dir")
+	out := &bytes.Buffer{}
+	ioStreams := &genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: out}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(), nil, metav1.NamespaceDefault)
+	cmd := runner.NewRunner(p, ioStreams, pullCmd()).Cmd()
+	cmd.SetArgs([]string{
+		strings.TrimPrefix(registry.URL, "http://") + "/source/bundle:latest", dir, "--insecure-registry",
+	})
+
+	err = cmd.ExecuteContext(context.TODO())
+	g.Expect(err).ToNot(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("points outside of"))
+	g.Expect(filepath.Join(outside, "escape.txt")).ToNot(BeAnExistingFile())
+	g.Expect(filepath.Join(dir, "escape.txt")).ToNot(BeAnExistingFile())
+}
+
+func TestListFilesOutput(t *testing.T) {
+	g := NewWithT(t)
+
+	img, err := stub.LayerImage(
+		&tar.Header{Typeflag: tar.TypeDir, Name: "pkg/", Mode: 0o755},
+		&tar.Header{Typeflag: tar.TypeReg, Name: "pkg/file.go", Mode: 0o644, Size: 1},
+		&tar.Header{Typeflag: tar.TypeSymlink, Name: "link.go", Linkname: "pkg/file.go", Mode: 0o777},
+	)
+	g.Expect(err).To(BeNil())
+	registry := serveImage(g, img)
+	defer registry.Close()
+
+	out := &bytes.Buffer{}
+	ioStreams := &genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: out}
+	p := params.NewParamsForTest(fake.NewSimpleClientset(), shpfake.NewSimpleClientset(), nil, metav1.NamespaceDefault)
+	cmd := runner.NewRunner(p, ioStreams, listFilesCmd()).Cmd()
+	cmd.SetArgs([]string{strings.TrimPrefix(registry.URL, "http://") + "/source/bundle:latest", "--insecure-registry"})
+
+	g.Expect(cmd.ExecuteContext(context.TODO())).To(Succeed())
+	g.Expect(out.String()).To(Equal("" +
+		"       1 B  pkg/file.go\n" +
+		"      link  link.go -> pkg/file.go\n" +
+		"Total: 1 file, 1 B\n",
+	))
+}
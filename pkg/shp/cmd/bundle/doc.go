@@ -0,0 +1,2 @@
+// Package bundle contains types and functions for bundle cobra sub-command
+package bundle
@@ -0,0 +1,91 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/bundle"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+// InspectCommand contains data input from user for inspect sub-command
+type InspectCommand struct {
+	cmd *cobra.Command
+
+	imageOptions
+}
+
+func inspectCmd() runner.SubCommand {
+	inspectCmd := &InspectCommand{
+		cmd: &cobra.Command{
+			Use:   "inspect [<image>] [flags]",
+			Short: "Show details of a source bundle image",
+			Long: `Show details of a source bundle image: digest, size, creation time and annotations.
+
+The image is either informed directly, or resolved from the BuildRun that pulled it with "--buildrun",
+pinned to the digest recorded on its status.`,
+			Args: cobra.MaximumNArgs(1),
+		},
+	}
+
+	inspectCmd.addFlags(inspectCmd.cmd.Flags())
+
+	return inspectCmd
+}
+
+// Cmd returns cobra command object
+func (c *InspectCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *InspectCommand) Complete(params *params.Params, io *genericclioptions.IOStreams, args []string) error {
+	_, err := c.complete(args, 0)
+	return err
+}
+
+// Validate validates data input by user
+func (c *InspectCommand) Validate() error {
+	return c.validate()
+}
+
+// Run executes inspect sub-command logic
+func (c *InspectCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	image, img, err := c.fetch(c.cmd.Context(), params)
+	if err != nil {
+		return err
+	}
+	details, err := bundle.Inspect(img)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(ioStreams.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(writer, "Image:\t%s\n", image)
+	fmt.Fprintf(writer, "Digest:\t%s\n", details.Digest)
+	fmt.Fprintf(writer, "Size:\t%s\n", streamer.HumanBytes(details.Size))
+	fmt.Fprintf(writer, "Layers:\t%d\n", details.Layers)
+	fmt.Fprintf(writer, "Created:\t%s\n", details.Created.UTC().Format(time.RFC3339))
+	if len(details.Annotations) == 0 {
+		fmt.Fprintf(writer, "Annotations:\t<none>\n")
+		return writer.Flush()
+	}
+	fmt.Fprintf(writer, "Annotations:\t\n")
+	keys := make([]string, 0, len(details.Annotations))
+	for key := range details.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(writer, "  %s:\t%s\n", key, details.Annotations[key])
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/bundle"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+// ListFilesCommand contains data input from user for list-files sub-command
+type ListFilesCommand struct {
+	cmd *cobra.Command
+
+	imageOptions
+}
+
+func listFilesCmd() runner.SubCommand {
+	listFilesCmd := &ListFilesCommand{
+		cmd: &cobra.Command{
+			Use:   "list-files [<image>] [flags]",
+			Short: "List the files of a source bundle image",
+			Long: `List the files of a source bundle image, with their sizes, as the BuildRun receives them.
+
+The image is either informed directly, or resolved from the BuildRun that pulled it with "--buildrun",
+pinned to the digest recorded on its status.`,
+			Args: cobra.MaximumNArgs(1),
+		},
+	}
+
+	listFilesCmd.addFlags(listFilesCmd.cmd.Flags())
+
+	return listFilesCmd
+}
+
+// Cmd returns cobra command object
+func (c *ListFilesCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *ListFilesCommand) Complete(params *params.Params, io *genericclioptions.IOStreams, args []string) error {
+	_, err := c.complete(args, 0)
+	return err
+}
+
+// Validate validates data input by user
+func (c *ListFilesCommand) Validate() error {
+	return c.validate()
+}
+
+// Run executes list-files sub-command logic
+func (c *ListFilesCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	_, img, err := c.fetch(c.cmd.Context(), params)
+	if err != nil {
+		return err
+	}
+	entries, err := bundle.ListFiles(img)
+	if err != nil {
+		return err
+	}
+	return streamer.PrintFileList(ioStreams.Out, entries)
+}
@@ -0,0 +1,82 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/shipwright-io/cli/pkg/shp/bundle"
+	"github.com/shipwright-io/cli/pkg/shp/cmd/runner"
+	"github.com/shipwright-io/cli/pkg/shp/params"
+)
+
+// PullCommand contains data input from user for pull sub-command
+type PullCommand struct {
+	cmd *cobra.Command
+
+	imageOptions
+
+	dir string // target directory
+}
+
+func pullCmd() runner.SubCommand {
+	pullCmd := &PullCommand{
+		cmd: &cobra.Command{
+			Use:   "pull [<image>] <dir> [flags]",
+			Short: "Pull a source bundle image and extract its files on a local directory",
+			Long: `Pull a source bundle image and extract its files on a local directory, created when it does not
+exist, the same files the BuildRun receives.
+
+The image is either informed directly, or resolved from the BuildRun that pulled it with "--buildrun",
+pinned to the digest recorded on its status.`,
+			Args: cobra.RangeArgs(1, 2),
+		},
+	}
+
+	pullCmd.addFlags(pullCmd.cmd.Flags())
+
+	return pullCmd
+}
+
+// Cmd returns cobra command object
+func (c *PullCommand) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete fills in data provided by user
+func (c *PullCommand) Complete(params *params.Params, io *genericclioptions.IOStreams, args []string) error {
+	args, err := c.complete(args, 1)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("the target directory must be informed")
+	}
+	c.dir = args[0]
+	return nil
+}
+
+// Validate validates data input by user
+func (c *PullCommand) Validate() error {
+	return c.validate()
+}
+
+// Run executes pull sub-command logic
+func (c *PullCommand) Run(params *params.Params, ioStreams *genericclioptions.IOStreams) error {
+	image, img, err := c.fetch(c.cmd.Context(), params)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	if err = bundle.Extract(img, c.dir); err != nil {
+		return fmt.Errorf("unable to extract source bundle '%s': %w", image, err)
+	}
+
+	fmt.Fprintf(ioStreams.Out, "Source bundle '%s' extracted on '%s'\n", image, c.dir)
+	return nil
+}
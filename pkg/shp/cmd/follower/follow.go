@@ -10,15 +10,13 @@ import (
 
 	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
 	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+	"github.com/shipwright-io/cli/pkg/shp/archive"
 	"github.com/shipwright-io/cli/pkg/shp/reactor"
 	"github.com/shipwright-io/cli/pkg/shp/tail"
-	"github.com/shipwright-io/cli/pkg/shp/util"
 
 	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 )
@@ -29,14 +27,36 @@ type Follower struct {
 	buildRun       types.NamespacedName         // qualified object name
 	ioStreams      *genericclioptions.IOStreams // io-streams instance
 	pw             *reactor.PodWatcher          // pod-watcher instance
+	bw             *reactor.BuildRunWatcher     // buildrun-watcher instance
 	clientset      kubernetes.Interface         // kubernetes api-client
 	buildClientset buildclientset.Interface     // shipwright api-client
 
 	logTail         *tail.Tail      // follow container logs
-	tailLogsStarted map[string]bool // controls tail instance per container
+	tailLogsStarted map[string]bool // controls tail instance per pod and container
+	logFormat       tail.Format     // log output format
+	formatter       tail.Formatter  // renders messages using the log output format
+	timestamps      bool            // show time elapsed since the buildrun start
+	buildRunPrefix  []string        // buildrun names sharing the output, steps are prefixed when set
 
-	logLock             sync.Mutex // avoiding race condition to print logs
-	enteredRunningState bool       // target pod is running
+	events          bool                             // kubernetes events are shown inline
+	eventWatchers   map[string]*reactor.EventWatcher // events watcher per object involved on the build
+	eventLock       sync.Mutex                       // protects the objects involved on the build
+	podName         string                           // build pod name
+	watchersStarted bool                             // background watchers have started
+
+	brLock       sync.Mutex              // protects the buildrun state observed
+	lastBuildRun *buildv1alpha1.BuildRun // last buildrun state observed by the buildrun-watcher
+	brDeleted    bool                    // buildrun has been deleted
+	brDone       bool                    // buildrun has reached a final state
+	brErr        error                   // buildrun failure observed by the buildrun-watcher
+	failedPod    *corev1.Pod             // failed build pod, reported when the buildrun reaches a final state
+	startTime    time.Time               // buildrun start time, reference for elapsed timestamps
+
+	attempts    []types.UID        // build pods observed, each one is a BuildRun attempt
+	currentPod  *corev1.Pod        // build pod of the latest attempt
+	runningPods map[types.UID]bool // build pods which have entered the running state
+
+	output *tail.Output // avoiding race condition to print logs, shared with the log tail
 }
 
 // NewFollower returns a Follower instance.
@@ -56,120 +76,499 @@ func NewFollower(
 		clientset:      clientset,
 		buildClientset: buildClientset,
 
+		bw: reactor.NewBuildRunWatcher(ctx, buildClientset, buildRun.Namespace, buildRun.Name),
+
 		logTail:         tail.NewTail(ctx, clientset),
-		logLock:         sync.Mutex{},
+		output:          tail.NewOutput(),
 		tailLogsStarted: map[string]bool{},
+		eventWatchers:   map[string]*reactor.EventWatcher{},
+		runningPods:     map[types.UID]bool{},
+		logFormat:       tail.FormatText,
 	}
+	f.formatter, _ = tail.NewFormatter(f.logFormat)
+	f.logTail.SetBuildRun(buildRun.Name)
+	f.logTail.SetStdout(ioStreams.Out)
+	f.logTail.SetStderr(ioStreams.ErrOut)
+	f.logTail.SetOutput(f.output)
 
 	f.pw.WithOnPodModifiedFn(f.OnEvent)
 	f.pw.WithTimeoutPodFn(f.OnTimeout)
 	f.pw.WithNoPodEventsYetFn(f.OnNoPodEventsYet)
 
+	f.bw.WithOnStartedFn(f.OnBuildRunStarted)
+	f.bw.WithOnSucceededFn(f.onBuildRunDone(func(br *buildv1alpha1.BuildRun) (string, error) {
+		return fmt.Sprintf("BuildRun %q has succeeded!\n", br.Name), nil
+	}))
+	f.bw.WithOnFailedFn(f.onBuildRunDone(func(br *buildv1alpha1.BuildRun) (string, error) {
+		reason, message := "", ""
+		if c := br.Status.GetCondition(buildv1alpha1.Succeeded); c != nil {
+			reason, message = c.Reason, c.Message
+		}
+		return fmt.Sprintf("BuildRun %q has failed: %s %s\n", br.Name, reason, message),
+			fmt.Errorf("buildrun %q has failed: %s", br.Name, reason)
+	}))
+	f.bw.WithOnCanceledFn(f.onBuildRunDone(func(br *buildv1alpha1.BuildRun) (string, error) {
+		return fmt.Sprintf("BuildRun %q has been canceled.\n", br.Name), nil
+	}))
+	f.bw.WithOnDeletedFn(f.onBuildRunDone(func(br *buildv1alpha1.BuildRun) (string, error) {
+		f.brLock.Lock()
+		f.brDeleted = true
+		f.brLock.Unlock()
+		return fmt.Sprintf("BuildRun %q has been deleted.\n", br.Name), nil
+	}))
+
 	return f
 }
 
 // GetLogLock returns the mutex used for coordinating access to log buffers.
-func (f *Follower) GetLogLock() *sync.Mutex {
-	return &f.logLock
+func (f *Follower) GetLogLock() sync.Locker {
+	return f.output
+}
+
+// SetOutput shares the output with other followers writing on the same stream.
+func (f *Follower) SetOutput(o *tail.Output) {
+	f.output = o
+	f.logTail.SetOutput(o)
+}
+
+// SetLogFormat sets the output format for container logs and messages.
+func (f *Follower) SetLogFormat(format tail.Format) error {
+	formatter, err := tail.NewFormatter(format)
+	if err != nil {
+		return err
+	}
+	f.logFormat = format
+	f.formatter = formatter
+	f.logTail.SetFormatter(formatter)
+	return nil
+}
+
+// WithArchive stores a copy of the followed container logs on the informed archive.
+func (f *Follower) WithArchive(a *archive.Archive) *Follower {
+	f.logTail.WithArchive(a)
+	return f
+}
+
+// SetColor enables colorized step prefixes, only applicable to the text log format.
+func (f *Follower) SetColor(enabled bool) {
+	if tf, ok := f.formatter.(*tail.TextFormatter); ok {
+		tf.SetColor(enabled)
+	}
+}
+
+// SetBuildRunPrefix prefixes the step names with the BuildRun name, aligned to the informed names
+// of all BuildRuns sharing the output, only applicable to the text log format.
+func (f *Follower) SetBuildRunPrefix(buildRuns []string) {
+	f.buildRunPrefix = buildRuns
+}
+
+// SetEvents enables showing the Kubernetes Events of the BuildRun, its TaskRun and the build pod
+// inline with the logs.
+func (f *Follower) SetEvents(enabled bool) {
+	f.events = enabled
+	if enabled {
+		f.watchEvents("BuildRun", f.buildRun.Name, "")
+	}
+}
+
+// watchEvents watches the Kubernetes Events about the informed object involved on the build, each
+// object has its own watcher selecting its events on the API server. Started in the background
+// when the other watchers are running already.
+func (f *Follower) watchEvents(kind, name string, uid types.UID) {
+	if !f.events || name == "" {
+		return
+	}
+	f.eventLock.Lock()
+	defer f.eventLock.Unlock()
+	key := fmt.Sprintf("%s/%s/%s", kind, name, uid)
+	if _, exists := f.eventWatchers[key]; exists {
+		return
+	}
+	ew := reactor.NewEventWatcher(f.ctx, f.clientset, f.buildRun.Namespace).
+		WithInvolvedObject(kind, name, uid).
+		WithOnEventFn(f.OnKubeEvent)
+	f.eventWatchers[key] = ew
+	if f.watchersStarted {
+		f.startEventWatcher(ew)
+	}
+}
+
+// startEventWatcher starts the informed events watcher in the background.
+func (f *Follower) startEventWatcher(ew *reactor.EventWatcher) {
+	go func() {
+		if err := ew.Start(); err != nil {
+			f.Log(fmt.Sprintf("error watching events for BuildRun %q: %s\n", f.buildRun.Name, err.Error()))
+		}
+	}()
+}
+
+// SetTimestamps enables showing the time elapsed since the BuildRun start on each log line, only
+// applicable to the text log format.
+func (f *Follower) SetTimestamps(enabled bool) {
+	f.timestamps = enabled
+}
+
+// buildRunStartTime returns the time the BuildRun has started, when not possible to determine, the
+// pod creation time is used instead. The start time is retrieved once per BuildRun.
+func (f *Follower) buildRunStartTime(pod *corev1.Pod) time.Time {
+	f.brLock.Lock()
+	if f.startTime.IsZero() && f.lastBuildRun != nil && f.lastBuildRun.HasStarted() {
+		f.startTime = f.lastBuildRun.Status.StartTime.Time
+	}
+	startTime := f.startTime
+	f.brLock.Unlock()
+	if !startTime.IsZero() {
+		return startTime
+	}
+
+	brClient := f.buildClientset.ShipwrightV1alpha1().BuildRuns(f.buildRun.Namespace)
+	br, err := brClient.Get(f.ctx, f.buildRun.Name, metav1.GetOptions{})
+	switch {
+	case err != nil:
+		return pod.GetCreationTimestamp().Time
+	case br.Status.StartTime != nil:
+		startTime = br.Status.StartTime.Time
+	default:
+		startTime = br.GetCreationTimestamp().Time
+	}
+	f.brLock.Lock()
+	f.startTime = startTime
+	f.brLock.Unlock()
+	return startTime
 }
 
 // Log prints a message
 func (f *Follower) Log(msg string) {
 	// concurrent fmt.Fprintf(r.ioStream.Out...) calls need locking to avoid data races, as we 'write' to the stream
-	f.logLock.Lock()
-	defer f.logLock.Unlock()
-	fmt.Fprint(f.ioStreams.Out, msg)
+	f.output.Lock()
+	defer f.output.Unlock()
+	// messages are not part of the step logs, the group of the step being written is closed
+	f.output.CloseGroup(f.ioStreams.Out, f.formatter)
+	f.formatter.Message(f.ioStreams.Out, f.buildRun.Name, msg)
 }
 
 // tailLogs start tailing logs for each container name in init-containers and containers, if not
 // started already.
 func (f *Follower) tailLogs(pod *corev1.Pod) {
 	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
+	if tf, ok := f.formatter.(*tail.TextFormatter); ok {
+		names := []string{}
+		for _, container := range containers {
+			names = append(names, container.Name)
+		}
+		tf.AlignContainers(names)
+		if len(f.buildRunPrefix) > 0 {
+			tf.AlignBuildRuns(f.buildRunPrefix)
+		}
+		if f.timestamps {
+			tf.SetTimestamps(f.buildRunStartTime(pod))
+		}
+	}
 	for _, container := range containers {
-		if _, exists := f.tailLogsStarted[container.Name]; exists {
+		key := fmt.Sprintf("%s/%s", pod.GetUID(), container.Name)
+		if _, exists := f.tailLogsStarted[key]; exists {
 			continue
 		}
-		f.tailLogsStarted[container.Name] = true
+		f.tailLogsStarted[key] = true
 		f.logTail.Start(pod.GetNamespace(), pod.GetName(), container.Name)
 	}
 }
 
+// dumpLogs writes the complete logs of all pod containers using the log output format.
+func (f *Follower) dumpLogs(pod *corev1.Pod) {
+	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
+	for _, c := range containers {
+		if err := f.logTail.Dump(pod.GetNamespace(), pod.GetName(), c.Name); err != nil {
+			f.Log(fmt.Sprintf("could not get logs for container %q: %s", c.Name, err.Error()))
+		}
+	}
+}
+
 // Stop stop log tail instance.
 func (f *Follower) Stop() {
 	f.logTail.Stop()
 	f.pw.Stop()
+	f.bw.Stop()
+	f.eventLock.Lock()
+	defer f.eventLock.Unlock()
+	for _, ew := range f.eventWatchers {
+		ew.Stop()
+	}
+}
+
+// observeBuildRun records the BuildRun state informed by the buildrun-watcher.
+func (f *Follower) observeBuildRun(br *buildv1alpha1.BuildRun, done bool) {
+	if ref := br.Status.LatestTaskRunRef; ref != nil {
+		f.watchEvents("TaskRun", *ref, "")
+	}
+	f.brLock.Lock()
+	defer f.brLock.Unlock()
+	f.lastBuildRun = br
+	if done {
+		f.brDone = true
+	}
+}
+
+// holdPodFailure records the failed build pod while the BuildRun has not reached a final state, it
+// might still be retried with a new pod. Returns the last BuildRun state observed, whether it has
+// been deleted, and whether the final state has been observed already, then the pod is not held.
+func (f *Follower) holdPodFailure(pod *corev1.Pod) (*buildv1alpha1.BuildRun, bool, bool) {
+	f.brLock.Lock()
+	defer f.brLock.Unlock()
+	if !f.brDone {
+		f.failedPod = pod
+	}
+	return f.lastBuildRun, f.brDeleted, f.brDone
+}
+
+// releasePodFailure returns the failed build pod held, if any, with the last BuildRun state
+// observed and whether it has been deleted.
+func (f *Follower) releasePodFailure() (*corev1.Pod, *buildv1alpha1.BuildRun, bool) {
+	f.brLock.Lock()
+	defer f.brLock.Unlock()
+	pod := f.failedPod
+	f.failedPod = nil
+	return pod, f.lastBuildRun, f.brDeleted
+}
+
+// reportPodFailure reports the build pod failure, together with the reason when the BuildRun has
+// been deleted or canceled, and stops the follower.
+func (f *Follower) reportPodFailure(pod *corev1.Pod, br *buildv1alpha1.BuildRun, deleted bool) error {
+	var msg string
+	var err error
+	switch {
+	case deleted || (br != nil && br.DeletionTimestamp != nil):
+		msg = fmt.Sprintf("BuildRun %q has been deleted.\n", f.buildRun.Name)
+	case br != nil && br.IsCanceled():
+		msg = fmt.Sprintf("BuildRun %q has been canceled.\n", br.Name)
+	case pod.DeletionTimestamp != nil:
+		msg = fmt.Sprintf("Pod %q has been deleted.\n", pod.GetName())
+	default:
+		msg = fmt.Sprintf("Pod %q has failed!\n", pod.GetName())
+		podBytes, err2 := json.MarshalIndent(pod, "", "    ")
+		if err2 == nil {
+			msg = fmt.Sprintf("Pod %q has failed!\nPod JSON:\n%s\n", pod.GetName(), string(podBytes))
+		}
+		err = fmt.Errorf("build pod %q has failed", pod.GetName())
+	}
+	f.Log(msg)
+	f.Stop()
+	return err
+}
+
+// hasPod checks whether the build pod has been created, either observed by the pod-watcher or found
+// by listing the pods of the BuildRun.
+func (f *Follower) hasPod() bool {
+	if f.getPodName() != "" {
+		return true
+	}
+	pods, err := f.clientset.CoreV1().Pods(f.buildRun.Namespace).List(f.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", buildv1alpha1.LabelBuildRun, f.buildRun.Name),
+	})
+	return err == nil && len(pods.Items) > 0
+}
+
+// OnBuildRunStarted reacts to the BuildRun start, recording its state.
+func (f *Follower) OnBuildRunStarted(br *buildv1alpha1.BuildRun) error {
+	f.observeBuildRun(br, false)
+	return nil
+}
+
+// settlePodFailure reports the build pod failure held, if any. Returns whether a failure has been
+// reported.
+func (f *Follower) settlePodFailure() bool {
+	pod, br, deleted := f.releasePodFailure()
+	if pod == nil {
+		return false
+	}
+	_ = f.reportPodFailure(pod, br, deleted)
+	return true
+}
+
+// onBuildRunDone returns the function reacting to the BuildRun final state. When the build pod
+// exists, the pod-watcher reports the outcome together with the logs, or the build pod failure held
+// meanwhile is reported now. Otherwise, as in validation failures or a BuildRun never scheduled, the
+// message is shown and the follower stops.
+func (f *Follower) onBuildRunDone(
+	outcome func(br *buildv1alpha1.BuildRun) (string, error),
+) reactor.OnBuildRunEventFn {
+	return func(br *buildv1alpha1.BuildRun) error {
+		msg, err := outcome(br)
+		f.observeBuildRun(br, true)
+		if f.settlePodFailure() || f.hasPod() {
+			return nil
+		}
+		f.brLock.Lock()
+		f.brErr = err
+		f.brLock.Unlock()
+		f.Log(msg)
+		f.Stop()
+		return nil
+	}
+}
+
+// Err returns the BuildRun failure observed when the build pod has never been created.
+func (f *Follower) Err() error {
+	f.brLock.Lock()
+	defer f.brLock.Unlock()
+	return f.brErr
+}
+
+// getPodName returns the build pod name, empty when not known yet.
+func (f *Follower) getPodName() string {
+	f.eventLock.Lock()
+	defer f.eventLock.Unlock()
+	return f.podName
+}
+
+// OnKubeEvent prints the informed Kubernetes Event inline with the logs.
+func (f *Follower) OnKubeEvent(event *corev1.Event) {
+	msg := fmt.Sprintf("[event] %s %s %s/%s: %s",
+		event.Type,
+		event.Reason,
+		strings.ToLower(event.InvolvedObject.Kind),
+		event.InvolvedObject.Name,
+		strings.TrimSpace(event.Message),
+	)
+	if event.Count > 1 {
+		msg = fmt.Sprintf("%s (x%d)", msg, event.Count)
+	}
+	f.Log(msg + "\n")
+}
+
+// StartWatchers starts watching the BuildRun, and the Kubernetes Events when enabled, in the
+// background. The pod-watcher is started by the caller.
+func (f *Follower) StartWatchers() {
+	f.eventLock.Lock()
+	defer f.eventLock.Unlock()
+	if f.watchersStarted {
+		return
+	}
+	f.watchersStarted = true
+	go func() {
+		if err := f.bw.Start(); err != nil {
+			f.Log(fmt.Sprintf("error watching BuildRun %q: %s\n", f.buildRun.Name, err.Error()))
+			// the final state is not going to be observed, build pod failures are reported as is
+			f.brLock.Lock()
+			f.brDone = true
+			f.brLock.Unlock()
+			f.settlePodFailure()
+		}
+	}()
+	for _, ew := range f.eventWatchers {
+		f.startEventWatcher(ew)
+	}
+}
+
+// trackPod keeps track of the build pods, identified by UID, each new pod represents a new attempt
+// of the BuildRun, i.e. the TaskRun is retried or the pod is recreated. Returns false for pods
+// replaced by a newer attempt, and the attempt number when the pod is observed for the first time.
+func (f *Follower) trackPod(pod *corev1.Pod) (bool, int) {
+	f.eventLock.Lock()
+	defer f.eventLock.Unlock()
+
+	for _, uid := range f.attempts {
+		if uid == pod.GetUID() {
+			return uid == f.currentPod.GetUID(), 0
+		}
+	}
+	if f.currentPod != nil && pod.CreationTimestamp.Before(&f.currentPod.CreationTimestamp) {
+		return false, 0
+	}
+	f.attempts = append(f.attempts, pod.GetUID())
+	f.currentPod = pod
+	f.podName = pod.GetName()
+	return true, len(f.attempts)
+}
+
+// newerPod looks for a build pod created after the informed one, the latest attempt is returned.
+func (f *Follower) newerPod(pod *corev1.Pod) *corev1.Pod {
+	pods, err := f.clientset.CoreV1().Pods(f.buildRun.Namespace).List(f.ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", buildv1alpha1.LabelBuildRun, f.buildRun.Name),
+	})
+	if err != nil {
+		return nil
+	}
+	var newer *corev1.Pod
+	for i := range pods.Items {
+		candidate := &pods.Items[i]
+		if candidate.GetUID() == pod.GetUID() || !pod.CreationTimestamp.Before(&candidate.CreationTimestamp) {
+			continue
+		}
+		if newer == nil || newer.CreationTimestamp.Before(&candidate.CreationTimestamp) {
+			newer = candidate
+		}
+	}
+	return newer
 }
 
 // OnEvent reacts on pod state changes, to start and stop tailing container logs.
 func (f *Follower) OnEvent(pod *corev1.Pod) error {
+	current, attempt := f.trackPod(pod)
+	if attempt > 0 {
+		// the events of each build pod are watched, and of the TaskRun owning it
+		f.watchEvents("Pod", pod.GetName(), pod.GetUID())
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "TaskRun" {
+			f.watchEvents("TaskRun", owner.Name, "")
+		}
+	}
+	if !current {
+		// pods replaced by a newer attempt are not relevant anymore, their failure must not end
+		// the log following
+		return nil
+	}
+	if attempt > 1 {
+		if failed, _, _ := f.releasePodFailure(); failed != nil {
+			f.Log(fmt.Sprintf("Pod %q has failed, BuildRun %q is being retried\n", failed.GetName(), f.buildRun.Name))
+		}
+		f.Log(fmt.Sprintf("BuildRun %q attempt %d, following the new pod %q\n", f.buildRun.Name, attempt, pod.GetName()))
+	}
+
 	switch pod.Status.Phase {
 	case corev1.PodRunning:
-		if !f.enteredRunningState {
+		if !f.runningPods[pod.GetUID()] {
 			f.Log(fmt.Sprintf("Pod %q in %q state, starting up log tail", pod.GetName(), corev1.PodRunning))
-			f.enteredRunningState = true
+			f.runningPods[pod.GetUID()] = true
 			// graceful time to wait for container start
 			time.Sleep(3 * time.Second)
 			// start tailing container logs
 			f.tailLogs(pod)
 		}
 	case corev1.PodFailed:
-		msg := ""
-		var br *buildv1alpha1.BuildRun
-		err := wait.PollImmediate(1*time.Second, 15*time.Second, func() (done bool, err error) {
-			brClient := f.buildClientset.ShipwrightV1alpha1().BuildRuns(pod.Namespace)
-			br, err = brClient.Get(f.ctx, f.buildRun.Name, metav1.GetOptions{})
-			if err != nil {
-				if kerrors.IsNotFound(err) {
-					return true, nil
-				}
-				f.Log(fmt.Sprintf("error getting buildrun %q for pod %q: %s\n", f.buildRun.Name, pod.GetName(), err.Error()))
-				return false, nil
-			}
-			if br.IsDone() {
-				return true, nil
-			}
-			return false, nil
-		})
-		if err != nil {
-			f.Log(fmt.Sprintf("gave up trying to get a buildrun %q in a terminal state for pod %q, proceeding with pod failure processing", f.buildRun.Name, pod.GetName()))
-		}
-		switch {
-		case br == nil:
-			msg = fmt.Sprintf("BuildRun %q has been deleted.\n", br.Name)
-		case err == nil && br.IsCanceled():
-			msg = fmt.Sprintf("BuildRun %q has been canceled.\n", br.Name)
-		case (err == nil && br.DeletionTimestamp != nil) || (err != nil && kerrors.IsNotFound(err)):
-			msg = fmt.Sprintf("BuildRun %q has been deleted.\n", br.Name)
-		case pod.DeletionTimestamp != nil:
-			msg = fmt.Sprintf("Pod %q has been deleted.\n", pod.GetName())
-		default:
-			msg = fmt.Sprintf("Pod %q has failed!\n", pod.GetName())
-			podBytes, err2 := json.MarshalIndent(pod, "", "    ")
-			if err2 == nil {
-				msg = fmt.Sprintf("Pod %q has failed!\nPod JSON:\n%s\n", pod.GetName(), string(podBytes))
-			}
-			err = fmt.Errorf("build pod %q has failed", pod.GetName())
+		if newer := f.newerPod(pod); newer != nil {
+			f.Log(fmt.Sprintf("Pod %q has failed, BuildRun %q is being retried\n", pod.GetName(), f.buildRun.Name))
+			return f.OnEvent(newer)
+		}
+		br, deleted, done := f.holdPodFailure(pod)
+		if !done {
+			// the BuildRun might be retried with a new pod, followed when observed by the pod-watcher,
+			// otherwise the failure is reported when the buildrun-watcher observes the final state
+			return nil
 		}
 		// see if because of deletion or cancelation
-		f.Log(msg)
-		f.Stop()
-		return err
+		return f.reportPodFailure(pod, br, deleted)
 	case corev1.PodSucceeded:
 		// encountered scenarios where the build run quickly enough that the pod effectively skips the running state,
 		// or the events come in reverse order, and we never enter the tail
-		if !f.enteredRunningState {
+		if !f.runningPods[pod.GetUID()] {
 			f.Log(fmt.Sprintf("succeeded event for pod %q arrived before or in place of running event so dumping logs now", pod.GetName()))
-			var b strings.Builder
-			for _, c := range pod.Spec.Containers {
-				logs, err := util.GetPodLogs(f.ctx, f.clientset, *pod, c.Name)
-				if err != nil {
-					f.Log(fmt.Sprintf("could not get logs for container %q: %s", c.Name, err.Error()))
-					continue
+			switch f.logFormat {
+			case tail.FormatText:
+				var b strings.Builder
+				for _, c := range pod.Spec.Containers {
+					// the logs fetched are stored on the archives as well
+					logs, err := f.logTail.Fetch(pod.GetNamespace(), pod.GetName(), c.Name)
+					if err != nil {
+						f.Log(fmt.Sprintf("could not get logs for container %q: %s", c.Name, err.Error()))
+						continue
+					}
+					fmt.Fprintf(&b, "*** Pod %q, container %q: ***\n\n", pod.Name, c.Name)
+					fmt.Fprintln(&b, logs)
 				}
-				fmt.Fprintf(&b, "*** Pod %q, container %q: ***\n\n", pod.Name, c.Name)
-				fmt.Fprintln(&b, logs)
+				f.Log(b.String())
+			default:
+				f.dumpLogs(pod)
 			}
-			f.Log(b.String())
 		}
 		f.Log(fmt.Sprintf("Pod %q has succeeded!\n", pod.GetName()))
 		f.Stop()
@@ -236,5 +635,12 @@ func (f *Follower) OnNoPodEventsYet(podList *corev1.PodList) {
 
 // Start initiates the log following for the referenced BuildRun's Pod
 func (f *Follower) Start(lo metav1.ListOptions) (*corev1.Pod, error) {
-	return f.pw.Start(lo)
+	f.StartWatchers()
+	defer f.bw.Stop()
+
+	pod, err := f.pw.Start(lo)
+	if err == nil {
+		err = f.Err()
+	}
+	return pod, err
 }
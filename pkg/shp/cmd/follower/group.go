@@ -0,0 +1,118 @@
+package follower
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// syncWriter serializes the writes of concurrent followers sharing the same output.
+type syncWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// Write writes the informed bytes while holding the lock.
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.w.Write(p)
+}
+
+// SyncIOStreams returns a copy of the informed IOStreams, safe to be shared by several followers.
+func SyncIOStreams(ioStreams *genericclioptions.IOStreams) *genericclioptions.IOStreams {
+	return &genericclioptions.IOStreams{
+		In:     ioStreams.In,
+		Out:    &syncWriter{w: ioStreams.Out},
+		ErrOut: &syncWriter{w: ioStreams.ErrOut},
+	}
+}
+
+// Group follows several BuildRuns at once, each BuildRun has its own Follower instance, and
+// therefore its own PodWatcher and Tail, sharing the same output.
+type Group struct {
+	followers []*Follower // follower instance per BuildRun
+}
+
+// Followers exposes the followers of the group.
+func (g *Group) Followers() []*Follower {
+	return g.followers
+}
+
+// failed checks whether the BuildRun followed by the informed Follower has not succeeded, using
+// the error returned by the follower as a hint. BuildRuns without a final successful condition,
+// still unknown or missing, are not considered succeeded.
+func (g *Group) failed(f *Follower, err error) bool {
+	if err != nil {
+		return true
+	}
+	brClient := f.buildClientset.ShipwrightV1alpha1().BuildRuns(f.buildRun.Namespace)
+	br, err := brClient.Get(f.ctx, f.buildRun.Name, metav1.GetOptions{})
+	if err != nil {
+		return true
+	}
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	return c == nil || c.Status != corev1.ConditionTrue
+}
+
+// Start follows all BuildRuns concurrently, blocking until all of them are done. An error is
+// returned when at least one of the BuildRuns has failed.
+func (g *Group) Start() error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(g.followers))
+	for i, f := range g.followers {
+		wg.Add(1)
+		go func(i int, f *Follower) {
+			defer wg.Done()
+			_, errs[i] = f.Start(metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", buildv1alpha1.LabelBuildRun, f.buildRun.Name),
+			})
+		}(i, f)
+	}
+	wg.Wait()
+
+	failed := []string{}
+	for i, f := range g.followers {
+		if g.failed(f, errs[i]) {
+			failed = append(failed, f.buildRun.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d BuildRuns have failed: %s",
+			len(failed), len(g.followers), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Stop stops all followers.
+func (g *Group) Stop() {
+	for _, f := range g.followers {
+		f.Stop()
+	}
+}
+
+// NewGroup instantiate a Group with the informed followers, when more than one BuildRun is
+// followed the step names are prefixed with the BuildRun name, and the followers share the output.
+func NewGroup(followers ...*Follower) *Group {
+	if len(followers) > 1 {
+		names := []string{}
+		for _, f := range followers {
+			names = append(names, f.buildRun.Name)
+		}
+		output := tail.NewOutput()
+		for _, f := range followers {
+			f.SetBuildRunPrefix(names)
+			f.SetOutput(output)
+		}
+	}
+	return &Group{followers: followers}
+}
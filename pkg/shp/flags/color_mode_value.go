@@ -0,0 +1,43 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// ColorModeValue implements pflag.Value interface, to represent when the log output is colorized.
+type ColorModeValue struct {
+	modePtr *tail.ColorMode
+}
+
+// String shows the value as string.
+func (c *ColorModeValue) String() string {
+	if c.modePtr == nil {
+		return ""
+	}
+	return string(*c.modePtr)
+}
+
+// Set set the informed string as color mode, when supported.
+func (c *ColorModeValue) Set(value string) error {
+	mode := tail.ColorMode(value)
+	for _, m := range tail.ColorModes {
+		if m == mode {
+			*c.modePtr = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("'%s' is an invalid color mode, supported values are %s, %s or %s",
+		value, tail.ColorAuto, tail.ColorAlways, tail.ColorNever)
+}
+
+// Type analogous to the pflag "string".
+func (c *ColorModeValue) Type() string {
+	return "string"
+}
+
+// NewColorModeValue creates a new instance of ColorModeValue sharing an existing reference.
+func NewColorModeValue(modePtr *tail.ColorMode) *ColorModeValue {
+	return &ColorModeValue{modePtr: modePtr}
+}
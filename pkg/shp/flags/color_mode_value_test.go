@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+func TestColorModeValue(t *testing.T) {
+	g := NewWithT(t)
+
+	mode := tail.ColorAuto
+	v := NewColorModeValue(&mode)
+
+	err := v.Set(string(tail.ColorNever))
+	g.Expect(err).To(BeNil())
+	g.Expect(v.String()).To(Equal(string(tail.ColorNever)))
+	g.Expect(mode).To(Equal(tail.ColorNever))
+
+	err = v.Set("sometimes")
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(mode).To(Equal(tail.ColorNever))
+}
@@ -0,0 +1,47 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+// CompressionValue implements pflag.Value interface, to represent the compression applied on the
+// data streamed by the upload.
+type CompressionValue struct {
+	compressionPtr *streamer.Compression
+}
+
+// String shows the value as string.
+func (c *CompressionValue) String() string {
+	if c.compressionPtr == nil {
+		return ""
+	}
+	return string(*c.compressionPtr)
+}
+
+// Set set the informed string as compression, when supported.
+func (c *CompressionValue) Set(value string) error {
+	compression := streamer.Compression(value)
+	supported := []string{}
+	for _, s := range streamer.Compressions {
+		if s == compression {
+			*c.compressionPtr = compression
+			return nil
+		}
+		supported = append(supported, string(s))
+	}
+	return fmt.Errorf("'%s' is an invalid compression, supported values are: %s",
+		value, strings.Join(supported, ", "))
+}
+
+// Type analogous to the pflag "string".
+func (c *CompressionValue) Type() string {
+	return "string"
+}
+
+// NewCompressionValue creates a new instance of CompressionValue sharing an existing reference.
+func NewCompressionValue(compressionPtr *streamer.Compression) *CompressionValue {
+	return &CompressionValue{compressionPtr: compressionPtr}
+}
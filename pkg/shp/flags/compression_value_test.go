@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+)
+
+func TestCompressionValue(t *testing.T) {
+	g := NewWithT(t)
+
+	compression := streamer.CompressionAuto
+	v := NewCompressionValue(&compression)
+
+	err := v.Set(string(streamer.CompressionZstd))
+	g.Expect(err).To(BeNil())
+	g.Expect(v.String()).To(Equal(string(streamer.CompressionZstd)))
+	g.Expect(compression).To(Equal(streamer.CompressionZstd))
+
+	err = v.Set("bzip2")
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(compression).To(Equal(streamer.CompressionZstd))
+}
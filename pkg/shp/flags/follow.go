@@ -1,6 +1,7 @@
 package flags
 
 import (
+	"github.com/shipwright-io/cli/pkg/shp/tail"
 	"github.com/spf13/pflag"
 )
 
@@ -14,3 +15,61 @@ func FollowFlag(flags *pflag.FlagSet, follow *bool) {
 		"Start a build and watch its log until it completes or fails.",
 	)
 }
+
+// LogFormatFlag register the log format flag, recording the value on the informed format pointer.
+func LogFormatFlag(flags *pflag.FlagSet, format *tail.Format) {
+	flags.Var(
+		NewLogFormatValue(format),
+		"log-format",
+		"Log output format, either text, json, github or gitlab.",
+	)
+}
+
+// ColorFlag register the color flag, recording the value on the informed color mode pointer.
+func ColorFlag(flags *pflag.FlagSet, mode *tail.ColorMode) {
+	flags.Var(
+		NewColorModeValue(mode),
+		"color",
+		"Colorize the step prefixes of followed logs, either auto, always or never.",
+	)
+}
+
+// TimestampsFlag register the timestamps flag, recording the value on the informed boolean pointer.
+func TimestampsFlag(flags *pflag.FlagSet, timestamps *bool) {
+	flags.BoolVar(
+		timestamps,
+		"timestamps",
+		*timestamps,
+		"Show the time elapsed since the BuildRun start on each line of followed logs.",
+	)
+}
+
+// ArchiveLogsFlag register the archive logs flag, recording the value on the informed boolean pointer.
+func ArchiveLogsFlag(flags *pflag.FlagSet, archiveLogs *bool) {
+	flags.BoolVar(
+		archiveLogs,
+		"archive-logs",
+		*archiveLogs,
+		"Store a copy of the BuildRun logs on the local archive, served when the builder pod is gone.",
+	)
+}
+
+// SaveToFlag register the save-to flag, recording the value on the informed string pointer.
+func SaveToFlag(flags *pflag.FlagSet, dir *string) {
+	flags.StringVar(
+		dir,
+		"save-to",
+		*dir,
+		"Directory to export the BuildRun logs to, organized by namespace, BuildRun and Pod.",
+	)
+}
+
+// EventsFlag register the events flag, recording the value on the informed boolean pointer.
+func EventsFlag(flags *pflag.FlagSet, events *bool) {
+	flags.BoolVar(
+		events,
+		"events",
+		*events,
+		"Show the Kubernetes Events of the BuildRun, its TaskRun and Pod inline with followed logs.",
+	)
+}
@@ -0,0 +1,46 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+// LogFormatValue implements pflag.Value interface, to represent the log output format as a string
+// command-line flag, accepting only the formats supported by the tail package.
+type LogFormatValue struct {
+	formatPtr *tail.Format
+}
+
+// String shows the value as string.
+func (l *LogFormatValue) String() string {
+	if l.formatPtr == nil {
+		return ""
+	}
+	return string(*l.formatPtr)
+}
+
+// Set set the informed string as log format, when supported.
+func (l *LogFormatValue) Set(value string) error {
+	format := tail.Format(value)
+	if _, err := tail.NewFormatter(format); err != nil {
+		supported := []string{}
+		for _, f := range tail.Formats {
+			supported = append(supported, string(f))
+		}
+		return fmt.Errorf("%w, supported formats are: %s", err, strings.Join(supported, ", "))
+	}
+	*l.formatPtr = format
+	return nil
+}
+
+// Type analogous to the pflag "string".
+func (l *LogFormatValue) Type() string {
+	return "string"
+}
+
+// NewLogFormatValue creates a new instance of LogFormatValue sharing an existing reference.
+func NewLogFormatValue(formatPtr *tail.Format) *LogFormatValue {
+	return &LogFormatValue{formatPtr: formatPtr}
+}
@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/shipwright-io/cli/pkg/shp/tail"
+)
+
+func TestLogFormatValue(t *testing.T) {
+	g := NewWithT(t)
+
+	format := tail.FormatText
+	v := NewLogFormatValue(&format)
+
+	err := v.Set(string(tail.FormatJSON))
+	g.Expect(err).To(BeNil())
+	g.Expect(v.String()).To(Equal(string(tail.FormatJSON)))
+	g.Expect(format).To(Equal(tail.FormatJSON))
+
+	err = v.Set("xml")
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(format).To(Equal(tail.FormatJSON))
+}
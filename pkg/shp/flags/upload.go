@@ -0,0 +1,172 @@
+package flags
+
+import (
+	"github.com/shipwright-io/cli/pkg/shp/streamer"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ExcludeFlag register the repeatable exclude flag, recording the patterns on the informed slice.
+func ExcludeFlag(flags *pflag.FlagSet, patterns *[]string) {
+	flags.StringArrayVar(
+		patterns,
+		"exclude",
+		*patterns,
+		"Skip the local files matching the pattern (gitignore syntax) on upload, may be repeated.",
+	)
+}
+
+// IncludeFlag register the repeatable include flag, recording the patterns on the informed slice.
+func IncludeFlag(flags *pflag.FlagSet, patterns *[]string) {
+	flags.StringArrayVar(
+		patterns,
+		"include",
+		*patterns,
+		"Upload the local files matching the pattern (gitignore syntax) even when ignored, may be repeated.",
+	)
+}
+
+// NoGitIgnoreFlag register the no-gitignore flag, recording the value on the informed boolean pointer.
+func NoGitIgnoreFlag(flags *pflag.FlagSet, noGitIgnore *bool) {
+	flags.BoolVar(
+		noGitIgnore,
+		"no-gitignore",
+		*noGitIgnore,
+		"Upload the local files ignored by git, \".shpignore\" and exclude patterns still apply.",
+	)
+}
+
+// CompressionFlag register the compression flag, recording the value on the informed pointer.
+func CompressionFlag(flags *pflag.FlagSet, compression *streamer.Compression) {
+	flags.Var(
+		NewCompressionValue(compression),
+		"compression",
+		"Compression of the data streamed to the build pod, either auto, none, gzip or zstd.",
+	)
+}
+
+// VerboseFlag register the verbose flag, recording the value on the informed boolean pointer.
+func VerboseFlag(flags *pflag.FlagSet, verbose *bool) {
+	flags.BoolVar(
+		verbose,
+		"verbose",
+		*verbose,
+		"List the files extracted on the build pod, instead of showing the upload progress.",
+	)
+}
+
+// DryRunFlag register the dry-run flag, recording the value on the informed boolean pointer.
+func DryRunFlag(flags *pflag.FlagSet, dryRun *bool) {
+	flags.BoolVar(
+		dryRun,
+		"dry-run",
+		*dryRun,
+		"List the local files that would be uploaded, without creating a BuildRun.",
+	)
+}
+
+// TreeFlag register the tree flag, recording the value on the informed boolean pointer.
+func TreeFlag(flags *pflag.FlagSet, tree *bool) {
+	flags.BoolVar(
+		tree,
+		"tree",
+		*tree,
+		"On dry-run, show a tree of the directories with their sizes instead of the file list.",
+	)
+}
+
+// MaxSizeFlag register the max-size flag, recording the value on the informed quantity.
+func MaxSizeFlag(flags *pflag.FlagSet, maxSize *resource.QuantityValue) {
+	flags.Var(
+		maxSize,
+		"max-size",
+		"Abort the upload when the local files exceed the size informed, for instance 100Mi or 1G.",
+	)
+}
+
+// GitRefFlag register the git-ref flag, recording the value on the informed string pointer.
+func GitRefFlag(flags *pflag.FlagSet, gitRef *string) {
+	flags.StringVar(
+		gitRef,
+		"git-ref",
+		*gitRef,
+		"Upload the committed tree of the git ref (branch, tag or commit SHA) instead of the working directory.",
+	)
+}
+
+// KeepOnFailureFlag register the keep-on-failure flag, recording the value on the informed boolean
+// pointer.
+func KeepOnFailureFlag(flags *pflag.FlagSet, keepOnFailure *bool) {
+	flags.BoolVar(
+		keepOnFailure,
+		"keep-on-failure",
+		*keepOnFailure,
+		"Keep the BuildRun when the upload fails or is interrupted, instead of deleting it.",
+	)
+}
+
+// WatchFlag register the watch flag, recording the value on the informed boolean pointer.
+func WatchFlag(flags *pflag.FlagSet, watch *bool) {
+	flags.BoolVar(
+		watch,
+		"watch",
+		*watch,
+		"Watch the local files, and upload again following the logs whenever they change.",
+	)
+}
+
+// BundleCredentialsSecretFlag register the bundle-credentials-secret flag, recording the value on
+// the informed string pointer.
+func BundleCredentialsSecretFlag(flags *pflag.FlagSet, secret *string) {
+	flags.StringVar(
+		secret,
+		"bundle-credentials-secret",
+		*secret,
+		"Secret of type kubernetes.io/dockerconfigjson with the registry credentials to push the source bundle, like the Build's source credentials.",
+	)
+}
+
+// RegistryConfigFlag register the registry-config flag, recording the value on the informed string
+// pointer.
+func RegistryConfigFlag(flags *pflag.FlagSet, registryConfig *string) {
+	flags.StringVar(
+		registryConfig,
+		"registry-config",
+		*registryConfig,
+		"Docker configuration file (config.json) with the registry credentials to push the source bundle.",
+	)
+}
+
+// RegistryUsernameFlag register the registry-username flag, recording the value on the informed
+// string pointer.
+func RegistryUsernameFlag(flags *pflag.FlagSet, username *string) {
+	flags.StringVar(
+		username,
+		"registry-username",
+		*username,
+		"Registry username to push the source bundle, the password is read from stdin.",
+	)
+}
+
+// RegistryPasswordStdinFlag register the registry-password-stdin flag, recording the value on the
+// informed boolean pointer.
+func RegistryPasswordStdinFlag(flags *pflag.FlagSet, passwordStdin *bool) {
+	flags.BoolVar(
+		passwordStdin,
+		"registry-password-stdin",
+		*passwordStdin,
+		"Read the registry password to push the source bundle from stdin.",
+	)
+}
+
+// InsecureRegistryFlag register the insecure-registry flag, recording the value on the informed
+// boolean pointer.
+func InsecureRegistryFlag(flags *pflag.FlagSet, insecure *bool) {
+	flags.BoolVar(
+		insecure,
+		"insecure-registry",
+		*insecure,
+		"Push the source bundle to a registry using plain HTTP.",
+	)
+}
@@ -145,12 +145,8 @@ func (p *Params) Namespace() string {
 	return p.namespace
 }
 
-// NewFollower instantiate a new PodWatcher based on the current instance.
-func (p *Params) NewPodWatcher(ctx context.Context) (*reactor.PodWatcher, error) {
-	if p.pw != nil {
-		return p.pw, nil
-	}
-
+// newPodWatcher instantiate a new PodWatcher, not shared with other callers.
+func (p *Params) newPodWatcher(ctx context.Context) (*reactor.PodWatcher, error) {
 	to, err := p.RequestTimeout()
 	if err != nil {
 		return nil, err
@@ -159,10 +155,27 @@ func (p *Params) NewPodWatcher(ctx context.Context) (*reactor.PodWatcher, error)
 	if err != nil {
 		return nil, err
 	}
-	p.pw, err = reactor.NewPodWatcher(ctx, to, clientset, p.Namespace())
+	return reactor.NewPodWatcher(ctx, to, clientset, p.Namespace())
+}
+
+// NewPodWatcher instantiate a new PodWatcher based on the current instance.
+func (p *Params) NewPodWatcher(ctx context.Context) (*reactor.PodWatcher, error) {
+	if p.pw != nil {
+		return p.pw, nil
+	}
+
+	var err error
+	p.pw, err = p.newPodWatcher(ctx)
 	return p.pw, err
 }
 
+// ResetWatchers discards the PodWatcher and Follower instances, so new ones are instantiated for
+// a subsequent BuildRun.
+func (p *Params) ResetWatchers() {
+	p.pw = nil
+	p.follower = nil
+}
+
 // NewFollower instantiate a new Follower based on the current instance.
 func (p *Params) NewFollower(
 	ctx context.Context,
@@ -184,6 +197,34 @@ func (p *Params) NewFollower(
 	return p.follower, nil
 }
 
+// NewFollowerGroup instantiate a Group of followers for the informed BuildRuns, each follower has
+// its own PodWatcher, and the output streams are shared.
+func (p *Params) NewFollowerGroup(
+	ctx context.Context,
+	brs []types.NamespacedName,
+	ioStreams *genericclioptions.IOStreams,
+) (*follower.Group, error) {
+	clientset, err := p.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	buildClientset, err := p.ShipwrightClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	ioStreams = follower.SyncIOStreams(ioStreams)
+	followers := []*follower.Follower{}
+	for _, br := range brs {
+		pw, err := p.newPodWatcher(ctx)
+		if err != nil {
+			return nil, err
+		}
+		followers = append(followers, follower.NewFollower(ctx, br, ioStreams, pw, clientset, buildClientset))
+	}
+	return follower.NewGroup(followers...), nil
+}
+
 // NewParams creates a new instance of ShipwrightParams and returns it as
 // an interface value
 func NewParams() *Params {
@@ -0,0 +1,199 @@
+package reactor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	buildclientset "github.com/shipwright-io/build/pkg/client/clientset/versioned"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// BuildRunWatcher a function orchestrator based on watching a given BuildRun and reacting upon its
+// lifecycle, driven by the "Succeeded" condition. Complements the PodWatcher for the cases where the
+// build pod is never created.
+type BuildRunWatcher struct {
+	ctx       context.Context
+	clientset buildclientset.Interface
+	ns        string
+	name      string
+	stopCh    chan bool // stops the event loop execution
+	stopLock  sync.Mutex
+	stopped   bool
+	started   bool // the BuildRun has been observed as started
+
+	onStartedFn   []OnBuildRunEventFn
+	onSucceededFn []OnBuildRunEventFn
+	onFailedFn    []OnBuildRunEventFn
+	onCanceledFn  []OnBuildRunEventFn
+	onDeletedFn   []OnBuildRunEventFn
+}
+
+// OnBuildRunEventFn handles a BuildRun lifecycle event.
+type OnBuildRunEventFn func(br *buildv1alpha1.BuildRun) error
+
+// WithOnStartedFn sets the function executed when the BuildRun starts.
+func (b *BuildRunWatcher) WithOnStartedFn(fn OnBuildRunEventFn) *BuildRunWatcher {
+	b.onStartedFn = append(b.onStartedFn, fn)
+	return b
+}
+
+// WithOnSucceededFn sets the function executed when the BuildRun succeeds.
+func (b *BuildRunWatcher) WithOnSucceededFn(fn OnBuildRunEventFn) *BuildRunWatcher {
+	b.onSucceededFn = append(b.onSucceededFn, fn)
+	return b
+}
+
+// WithOnFailedFn sets the function executed when the BuildRun fails.
+func (b *BuildRunWatcher) WithOnFailedFn(fn OnBuildRunEventFn) *BuildRunWatcher {
+	b.onFailedFn = append(b.onFailedFn, fn)
+	return b
+}
+
+// WithOnCanceledFn sets the function executed when the BuildRun is canceled.
+func (b *BuildRunWatcher) WithOnCanceledFn(fn OnBuildRunEventFn) *BuildRunWatcher {
+	b.onCanceledFn = append(b.onCanceledFn, fn)
+	return b
+}
+
+// WithOnDeletedFn sets the function executed when the BuildRun is deleted.
+func (b *BuildRunWatcher) WithOnDeletedFn(fn OnBuildRunEventFn) *BuildRunWatcher {
+	b.onDeletedFn = append(b.onDeletedFn, fn)
+	return b
+}
+
+// call executes the informed functions, interrupting on the first error.
+func call(fns []OnBuildRunEventFn, br *buildv1alpha1.BuildRun) error {
+	for _, fn := range fns {
+		if err := fn(br); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleEvent applies user informed functions based on the BuildRun state, returns true when the
+// BuildRun has reached a final state.
+func (b *BuildRunWatcher) handleEvent(br *buildv1alpha1.BuildRun, eventType watch.EventType) (bool, error) {
+	if eventType == watch.Deleted || br.GetDeletionTimestamp() != nil {
+		return true, call(b.onDeletedFn, br)
+	}
+
+	if !b.started && br.HasStarted() {
+		b.started = true
+		if err := call(b.onStartedFn, br); err != nil {
+			return false, err
+		}
+	}
+
+	c := br.Status.GetCondition(buildv1alpha1.Succeeded)
+	switch {
+	case c == nil || c.Status == corev1.ConditionUnknown:
+		return false, nil
+	case c.Status == corev1.ConditionTrue:
+		return true, call(b.onSucceededFn, br)
+	case br.IsCanceled() || c.Reason == buildv1alpha1.BuildRunStateCancel:
+		return true, call(b.onCanceledFn, br)
+	default:
+		return true, call(b.onFailedFn, br)
+	}
+}
+
+// watch obtains the current BuildRun state, and opens the watch from its resourceVersion.
+func (b *BuildRunWatcher) watch() (watch.Interface, bool, error) {
+	client := b.clientset.ShipwrightV1alpha1().BuildRuns(b.ns)
+	br, err := client.Get(b.ctx, b.name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, true, call(b.onDeletedFn, &buildv1alpha1.BuildRun{
+				ObjectMeta: metav1.ObjectMeta{Namespace: b.ns, Name: b.name},
+			})
+		}
+		return nil, false, err
+	}
+	if done, err := b.handleEvent(br, watch.Modified); done || err != nil {
+		return nil, done, err
+	}
+
+	w, err := client.Watch(b.ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", b.name).String(),
+		ResourceVersion: br.GetResourceVersion(),
+	})
+	return w, false, err
+}
+
+// Start runs the event loop until the BuildRun reaches a final state, the watcher is stopped or
+// the context is done. The watch is resumed when closed by the API server.
+func (b *BuildRunWatcher) Start() error {
+	w, done, err := b.watch()
+	if done || err != nil {
+		return err
+	}
+	defer func() {
+		w.Stop()
+	}()
+
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error {
+				w.Stop()
+				for {
+					resumed, done, err := b.watch()
+					if done {
+						return err
+					}
+					if err == nil {
+						w = resumed
+						break
+					}
+					select {
+					case <-b.ctx.Done():
+						return nil
+					case <-b.stopCh:
+						return nil
+					case <-time.After(RetryInterval):
+					}
+				}
+				continue
+			}
+			br, ok := event.Object.(*buildv1alpha1.BuildRun)
+			if !ok || br.GetName() != b.name {
+				continue
+			}
+			if done, err := b.handleEvent(br, event.Type); done || err != nil {
+				return err
+			}
+		case <-b.ctx.Done():
+			return nil
+		case <-b.stopCh:
+			return nil
+		}
+	}
+}
+
+// Stop closes the stop channel, and stops the execution loop.
+func (b *BuildRunWatcher) Stop() {
+	b.stopLock.Lock()
+	defer b.stopLock.Unlock()
+	if !b.stopped {
+		close(b.stopCh)
+		b.stopped = true
+	}
+}
+
+// NewBuildRunWatcher instantiate BuildRunWatcher event-loop for the informed BuildRun.
+func NewBuildRunWatcher(
+	ctx context.Context,
+	clientset buildclientset.Interface,
+	ns string,
+	name string,
+) *BuildRunWatcher {
+	return &BuildRunWatcher{ctx: ctx, clientset: clientset, ns: ns, name: name, stopCh: make(chan bool)}
+}
@@ -0,0 +1,147 @@
+package reactor
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	buildv1alpha1 "github.com/shipwright-io/build/pkg/apis/build/v1alpha1"
+	shpfake "github.com/shipwright-io/build/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_BuildRunWatcher(t *testing.T) {
+	now := metav1.Now()
+	cancel := buildv1alpha1.BuildRunRequestedStatePtr(buildv1alpha1.BuildRunStateCancel)
+
+	tests := []struct {
+		name     string
+		br       *buildv1alpha1.BuildRun
+		expected []string
+	}{
+		{
+			name:     "not-found",
+			expected: []string{"deleted"},
+		},
+		{
+			name: "succeeded",
+			br: &buildv1alpha1.BuildRun{
+				Status: buildv1alpha1.BuildRunStatus{
+					StartTime:  &now,
+					Conditions: buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: corev1.ConditionTrue}},
+				},
+			},
+			expected: []string{"started", "succeeded"},
+		},
+		{
+			name: "failed-without-start",
+			br: &buildv1alpha1.BuildRun{
+				Status: buildv1alpha1.BuildRunStatus{
+					Conditions: buildv1alpha1.Conditions{{
+						Type:   buildv1alpha1.Succeeded,
+						Status: corev1.ConditionFalse,
+						Reason: "BuildStrategyNotFound",
+					}},
+				},
+			},
+			expected: []string{"failed"},
+		},
+		{
+			name: "canceled",
+			br: &buildv1alpha1.BuildRun{
+				Spec: buildv1alpha1.BuildRunSpec{State: cancel},
+				Status: buildv1alpha1.BuildRunStatus{
+					Conditions: buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: corev1.ConditionFalse}},
+				},
+			},
+			expected: []string{"canceled"},
+		},
+		{
+			name: "being-deleted",
+			br: &buildv1alpha1.BuildRun{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+			},
+			expected: []string{"deleted"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clientset := shpfake.NewSimpleClientset()
+			if test.br != nil {
+				test.br.Namespace = metav1.NamespaceDefault
+				test.br.Name = "br"
+				clientset = shpfake.NewSimpleClientset(test.br)
+			}
+
+			called := []string{}
+			record := func(name string) OnBuildRunEventFn {
+				return func(br *buildv1alpha1.BuildRun) error {
+					called = append(called, name)
+					return nil
+				}
+			}
+			bw := NewBuildRunWatcher(context.TODO(), clientset, metav1.NamespaceDefault, "br")
+			bw.WithOnStartedFn(record("started")).
+				WithOnSucceededFn(record("succeeded")).
+				WithOnFailedFn(record("failed")).
+				WithOnCanceledFn(record("canceled")).
+				WithOnDeletedFn(record("deleted"))
+
+			g.Expect(bw.Start()).To(BeNil())
+			g.Expect(called).To(Equal(test.expected))
+		})
+	}
+}
+
+func Test_BuildRunWatcherEvents(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	br := &buildv1alpha1.BuildRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "br"},
+	}
+	clientset := shpfake.NewSimpleClientset(br)
+
+	startedCh := make(chan bool, 1)
+	bw := NewBuildRunWatcher(ctx, clientset, metav1.NamespaceDefault, "br")
+	bw.WithOnStartedFn(func(br *buildv1alpha1.BuildRun) error {
+		startedCh <- true
+		return nil
+	})
+	succeeded := false
+	bw.WithOnSucceededFn(func(br *buildv1alpha1.BuildRun) error {
+		succeeded = true
+		return nil
+	})
+
+	doneCh := make(chan error)
+	go func() {
+		doneCh <- bw.Start()
+	}()
+
+	// the BuildRun is updated until the watch delivers the start
+	brClient := clientset.ShipwrightV1alpha1().BuildRuns(metav1.NamespaceDefault)
+	now := metav1.Now()
+	br.Status.StartTime = &now
+	g.Eventually(func() bool {
+		_, err := brClient.UpdateStatus(ctx, br, metav1.UpdateOptions{})
+		g.Expect(err).To(BeNil())
+		select {
+		case <-startedCh:
+			return true
+		default:
+			return false
+		}
+	}).Should(BeTrue())
+
+	br.Status.Conditions = buildv1alpha1.Conditions{{Type: buildv1alpha1.Succeeded, Status: corev1.ConditionTrue}}
+	_, err := brClient.UpdateStatus(ctx, br, metav1.UpdateOptions{})
+	g.Expect(err).To(BeNil())
+
+	g.Eventually(doneCh).Should(Receive(BeNil()))
+	g.Expect(succeeded).To(BeTrue())
+}
@@ -0,0 +1,220 @@
+package reactor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventWatcher watches the Kubernetes Events on a given namespace, optionally only the events about
+// a given object, reacting upon the events that are not skipped, works as a helper to show what
+// happens to the objects involved in a build.
+type EventWatcher struct {
+	ctx       context.Context
+	clientset kubernetes.Interface
+	ns        string
+	stopCh    chan bool // stops the event loop execution
+	stopLock  sync.Mutex
+	stopped   bool
+
+	resourceVersion string                  // last resourceVersion observed, watch resumes from it
+	involved        *corev1.ObjectReference // object the events are about, all events when not set
+
+	skipEventFn []SkipEventFn
+	onEventFn   []OnEventFn
+}
+
+// SkipEventFn a given event instance is informed and expects a boolean as return. When true is
+// returned the event is skipped.
+type SkipEventFn func(event *corev1.Event) bool
+
+// OnEventFn handles a event which has not been skipped.
+type OnEventFn func(event *corev1.Event)
+
+// WithInvolvedObject restricts the events to the ones about the informed object, filtered by the
+// API server. The UID is optional, when informed objects reusing the same name are told apart.
+func (e *EventWatcher) WithInvolvedObject(kind, name string, uid types.UID) *EventWatcher {
+	e.involved = &corev1.ObjectReference{Kind: kind, Name: name, UID: uid}
+	return e
+}
+
+// listOptions returns the options to list and watch the events, selecting the events about the
+// involved object, when informed.
+func (e *EventWatcher) listOptions(resourceVersion string) metav1.ListOptions {
+	opts := metav1.ListOptions{ResourceVersion: resourceVersion}
+	if e.involved != nil {
+		set := fields.Set{
+			"involvedObject.kind": e.involved.Kind,
+			"involvedObject.name": e.involved.Name,
+		}
+		if e.involved.UID != "" {
+			set["involvedObject.uid"] = string(e.involved.UID)
+		}
+		opts.FieldSelector = set.AsSelector().String()
+	}
+	return opts
+}
+
+// isInvolved checks whether the event is about the involved object, when informed.
+func (e *EventWatcher) isInvolved(event *corev1.Event) bool {
+	if e.involved == nil {
+		return true
+	}
+	obj := event.InvolvedObject
+	return obj.Kind == e.involved.Kind && obj.Name == e.involved.Name &&
+		(e.involved.UID == "" || obj.UID == e.involved.UID)
+}
+
+// WithSkipEventFn sets the skip function instance.
+func (e *EventWatcher) WithSkipEventFn(fn SkipEventFn) *EventWatcher {
+	e.skipEventFn = append(e.skipEventFn, fn)
+	return e
+}
+
+// WithOnEventFn sets the function executed for each event.
+func (e *EventWatcher) WithOnEventFn(fn OnEventFn) *EventWatcher {
+	e.onEventFn = append(e.onEventFn, fn)
+	return e
+}
+
+// handleEvent applies user informed functions against the informed event, unless skipped.
+func (e *EventWatcher) handleEvent(event *corev1.Event) {
+	e.resourceVersion = event.GetResourceVersion()
+	if !e.isInvolved(event) {
+		return
+	}
+	for _, fn := range e.skipEventFn {
+		if fn(event) {
+			return
+		}
+	}
+	for _, fn := range e.onEventFn {
+		fn(event)
+	}
+}
+
+// SortEvents sorts the events by the time they have last happened.
+func SortEvents(events []corev1.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+}
+
+// eventTime returns the last time the event has happened.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// watch opens the events watch from the last resourceVersion observed. When it is too old, or
+// relist is informed, the events are listed again to obtain a recent resourceVersion, events in
+// between are lost.
+func (e *EventWatcher) watch(relist bool) (watch.Interface, error) {
+	for {
+		if relist {
+			list, err := e.clientset.CoreV1().Events(e.ns).List(e.ctx, e.listOptions(""))
+			if err != nil {
+				return nil, err
+			}
+			e.resourceVersion = list.GetResourceVersion()
+		}
+		w, err := e.clientset.CoreV1().Events(e.ns).Watch(e.ctx, e.listOptions(e.resourceVersion))
+		if err == nil || !(kerrors.IsGone(err) || kerrors.IsResourceExpired(err)) {
+			return w, err
+		}
+		if e.ctx.Err() != nil {
+			return nil, e.ctx.Err()
+		}
+		relist = true
+	}
+}
+
+// Start lists the existing events and then watches for new ones, until stopped or the context is
+// done. Events are handled in the order they have happened. The watch is resumed when closed by
+// the API server.
+func (e *EventWatcher) Start() error {
+	list, err := e.clientset.CoreV1().Events(e.ns).List(e.ctx, e.listOptions(""))
+	if err != nil {
+		return err
+	}
+	SortEvents(list.Items)
+	for i := range list.Items {
+		e.handleEvent(&list.Items[i])
+	}
+	e.resourceVersion = list.GetResourceVersion()
+
+	w, err := e.watch(false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		w.Stop()
+	}()
+
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			switch {
+			case !ok, event.Type == watch.Error:
+				w.Stop()
+				relist := false
+				if event.Type == watch.Error {
+					err := kerrors.FromObject(event.Object)
+					relist = kerrors.IsGone(err) || kerrors.IsResourceExpired(err)
+				}
+				for {
+					resumed, err := e.watch(relist)
+					if err == nil {
+						w = resumed
+						break
+					}
+					select {
+					case <-e.ctx.Done():
+						return nil
+					case <-e.stopCh:
+						return nil
+					case <-time.After(RetryInterval):
+					}
+				}
+			case event.Type == watch.Added || event.Type == watch.Modified:
+				if ev, ok := event.Object.(*corev1.Event); ok {
+					e.handleEvent(ev)
+				}
+			}
+		case <-e.ctx.Done():
+			return nil
+		case <-e.stopCh:
+			return nil
+		}
+	}
+}
+
+// Stop closes the stop channel, and stops the execution loop.
+func (e *EventWatcher) Stop() {
+	e.stopLock.Lock()
+	defer e.stopLock.Unlock()
+	if !e.stopped {
+		close(e.stopCh)
+		e.stopped = true
+	}
+}
+
+// NewEventWatcher instantiate EventWatcher event-loop.
+func NewEventWatcher(ctx context.Context, clientset kubernetes.Interface, ns string) *EventWatcher {
+	return &EventWatcher{ctx: ctx, clientset: clientset, ns: ns, stopCh: make(chan bool)}
+}
@@ -0,0 +1,122 @@
+package reactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	fakekubetesting "k8s.io/client-go/testing"
+)
+
+func Test_EventWatcher(t *testing.T) {
+	g := NewWithT(t)
+
+	event := func(name string, lastSeen time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name},
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+	now := time.Now()
+	clientset := fake.NewSimpleClientset(
+		event("second", now),
+		event("skipped", now),
+		event("first", now.Add(-time.Minute)),
+	)
+
+	ew := NewEventWatcher(context.TODO(), clientset, metav1.NamespaceDefault)
+	ew.WithSkipEventFn(func(event *corev1.Event) bool {
+		return event.InvolvedObject.Name == "skipped"
+	})
+	received := []string{}
+	ew.WithOnEventFn(func(event *corev1.Event) {
+		received = append(received, event.InvolvedObject.Name)
+		if len(received) == 2 {
+			ew.Stop()
+		}
+	})
+
+	g.Expect(ew.Start()).To(BeNil())
+	g.Expect(received).To(Equal([]string{"first", "second"}))
+}
+
+func Test_EventWatcher_ResumeWatch(t *testing.T) {
+	g := NewWithT(t)
+	defer func(interval time.Duration) { RetryInterval = interval }(RetryInterval)
+	RetryInterval = 10 * time.Millisecond
+
+	clientset := fake.NewSimpleClientset()
+	watchers := make(chan *watch.FakeWatcher, 2)
+	clientset.PrependWatchReactor("events", func(action fakekubetesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFakeWithChanSize(1, false)
+		watchers <- w
+		return true, w, nil
+	})
+
+	ew := NewEventWatcher(context.TODO(), clientset, metav1.NamespaceDefault)
+	received := make(chan string, 2)
+	ew.WithOnEventFn(func(event *corev1.Event) {
+		received <- event.GetName()
+	})
+
+	doneCh := make(chan bool)
+	go func() {
+		g.Expect(ew.Start()).To(BeNil())
+		close(doneCh)
+	}()
+
+	first := <-watchers
+	first.Add(&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "first"}})
+	g.Eventually(received).Should(Receive(Equal("first")))
+
+	// the API server closes the watch, it should be resumed
+	first.Stop()
+	second := <-watchers
+	second.Add(&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "second"}})
+	g.Eventually(received).Should(Receive(Equal("second")))
+
+	ew.Stop()
+	<-doneCh
+}
+
+func Test_EventWatcher_InvolvedObject(t *testing.T) {
+	g := NewWithT(t)
+
+	event := func(name, podName string, uid types.UID) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: podName, UID: uid},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		event("other-attempt", "br-pod", "old"),
+		event("same-prefix", "br-pod-2", "other"),
+		event("involved", "br-pod", "uid"),
+	)
+
+	ew := NewEventWatcher(context.TODO(), clientset, metav1.NamespaceDefault).
+		WithInvolvedObject("Pod", "br-pod", "uid")
+	received := []string{}
+	ew.WithOnEventFn(func(event *corev1.Event) {
+		received = append(received, event.GetName())
+		ew.Stop()
+	})
+
+	g.Expect(ew.Start()).To(BeNil())
+	g.Expect(received).To(Equal([]string{"involved"}))
+
+	// the events are selected by the API server as well
+	for _, action := range clientset.Actions() {
+		if action, ok := action.(fakekubetesting.ListAction); ok {
+			g.Expect(action.GetListRestrictions().Fields.String()).To(
+				Equal("involvedObject.kind=Pod,involvedObject.name=br-pod,involvedObject.uid=uid"))
+		}
+	}
+}
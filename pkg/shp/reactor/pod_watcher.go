@@ -6,7 +6,9 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
@@ -19,6 +21,9 @@ const (
 	RequestTimeoutMessage = "request timeout has expired"
 )
 
+// RetryInterval is the interval between attempts to resume the watch.
+var RetryInterval = 2 * time.Second
+
 // PodWatcher a simple function orchestrator based on watching a given pod and reacting upon the
 // state modifications, should work as a helper to build business logic based on the build POD
 // changes.
@@ -34,6 +39,9 @@ type PodWatcher struct {
 	watcher     watch.Interface // client watch instance
 	listOpts    metav1.ListOptions
 
+	resourceVersion string               // last resourceVersion observed, watch resumes from it
+	seen            map[types.UID]string // last resourceVersion handled per pod
+
 	noPodEventsYetFn []NoPodEventsYetFn
 	toPodFn          []TimeoutPodFn
 	skipPodFn        []SkipPodFn
@@ -120,8 +128,81 @@ func (p *PodWatcher) handleEvent(pod *corev1.Pod, event watch.Event) error {
 	return nil
 }
 
+// process applies the skip functions and handles the informed watch event, keeping track of the
+// last resourceVersion observed.
+func (p *PodWatcher) process(event watch.Event) (*corev1.Pod, error) {
+	pod, ok := event.Object.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	p.resourceVersion = pod.GetResourceVersion()
+	p.seen[pod.GetUID()] = pod.GetResourceVersion()
+
+	for _, fn := range p.skipPodFn {
+		if fn(pod) {
+			return nil, nil
+		}
+	}
+	return pod, p.handleEvent(pod, event)
+}
+
+// interrupted waits the retry interval, returns true when the context is done or the watcher is
+// stopped in the meantime.
+func (p *PodWatcher) interrupted() bool {
+	select {
+	case <-p.ctx.Done():
+		return true
+	case <-p.stopCh:
+		return true
+	case <-time.After(RetryInterval):
+		return false
+	}
+}
+
+// resume re-establishes the watch after the API server has closed it. When possible the watch
+// resumes from the last resourceVersion observed, otherwise the pods are listed again and the ones
+// modified in the meantime are handled as modified events, before watching from the list
+// resourceVersion. It keeps retrying until it succeeds, or the watcher is interrupted.
+func (p *PodWatcher) resume(relist bool) (*corev1.Pod, error) {
+	for {
+		if relist {
+			podList, err := p.clientset.CoreV1().Pods(p.ns).List(p.ctx, p.listOpts)
+			if err == nil {
+				for i := range podList.Items {
+					pod := &podList.Items[i]
+					if p.seen[pod.GetUID()] == pod.GetResourceVersion() {
+						continue
+					}
+					if pod, err := p.process(watch.Event{Type: watch.Modified, Object: pod}); err != nil {
+						return pod, err
+					}
+				}
+				p.resourceVersion = podList.GetResourceVersion()
+				relist = false
+			}
+		}
+
+		if !relist {
+			listOpts := p.listOpts
+			listOpts.ResourceVersion = p.resourceVersion
+			w, err := p.clientset.CoreV1().Pods(p.ns).Watch(p.ctx, listOpts)
+			if err == nil {
+				p.watcher = w
+				return nil, nil
+			}
+			relist = kerrors.IsGone(err) || kerrors.IsResourceExpired(err)
+		}
+
+		if p.interrupted() {
+			// the event loop takes care of the interruption, the fake watcher never delivers events
+			p.watcher = watch.NewFake()
+			return nil, nil
+		}
+	}
+}
+
 // Start runs the event loop based on a watch instantiated against informed pod. In case of errors
-// the loop is interrupted.
+// the loop is interrupted. When the watch is closed by the API server, it is resumed transparently.
 func (p *PodWatcher) Start(listOpts metav1.ListOptions) (*corev1.Pod, error) {
 	p.listOpts = listOpts
 	w, err := p.clientset.CoreV1().Pods(p.ns).Watch(p.ctx, listOpts)
@@ -129,32 +210,33 @@ func (p *PodWatcher) Start(listOpts metav1.ListOptions) (*corev1.Pod, error) {
 		return nil, err
 	}
 	p.watcher = w
+	timeout := time.After(p.to)
 	for {
 		select {
 		// handling the regular pod modification events, which should trigger calling event functions
 		// accordinly
-		case event := <-p.watcher.ResultChan():
-			if event.Object == nil {
-				continue
-			}
-			pod, ok := event.Object.(*corev1.Pod)
+		case event, ok := <-p.watcher.ResultChan():
+			// the channel is closed when the watch expires, or the API server is restarted
 			if !ok {
+				if pod, err := p.resume(false); err != nil {
+					return pod, err
+				}
 				continue
 			}
-
-			if len(p.skipPodFn) > 0 {
-				skip := false
-				for _, fn := range p.skipPodFn {
-					if fn(pod) {
-						skip = true
-						break
-					}
-				}
-				if skip {
-					continue
+			// the API server informs errors, like the resourceVersion being too old, before closing
+			// the watch
+			if event.Type == watch.Error {
+				p.watcher.Stop()
+				err := kerrors.FromObject(event.Object)
+				if pod, err := p.resume(kerrors.IsGone(err) || kerrors.IsResourceExpired(err)); err != nil {
+					return pod, err
 				}
+				continue
+			}
+			if event.Object == nil {
+				continue
 			}
-			if err := p.handleEvent(pod, event); err != nil {
+			if pod, err := p.process(event); err != nil {
 				return pod, err
 			}
 		// watching over global context, when done is informed on the context it needs to reflect on
@@ -168,7 +250,7 @@ func (p *PodWatcher) Start(listOpts metav1.ListOptions) (*corev1.Pod, error) {
 
 		// handle k8s --request-timeout setting, converted to time.Duration, that is passed down to PodWatcher;
 		// if we have exceeded it, we exit
-		case <-time.After(p.to):
+		case <-timeout:
 			p.watcher.Stop()
 			for _, fn := range p.toPodFn {
 				fn(RequestTimeoutMessage)
@@ -220,5 +302,5 @@ func NewPodWatcher(
 	ns string,
 ) (*PodWatcher, error) {
 	//TODO don't think the have not received events yet ticker needs to be tunable, but leaving a TODO for now while we get feedback
-	return &PodWatcher{ctx: ctx, to: timeout, ns: ns, clientset: clientset, eventTicker: time.NewTicker(1 * time.Second), stopCh: make(chan bool), stopLock: sync.Mutex{}}, nil
+	return &PodWatcher{ctx: ctx, to: timeout, ns: ns, clientset: clientset, eventTicker: time.NewTicker(1 * time.Second), stopCh: make(chan bool), stopLock: sync.Mutex{}, seen: map[types.UID]string{}}, nil
 }
@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -32,6 +33,45 @@ func Test_PodWatcher_RequestTimeout(t *testing.T) {
 	g.Expect(called).To(BeTrue())
 }
 
+func Test_PodWatcher_RequestTimeoutDeadline(t *testing.T) {
+	g := NewWithT(t)
+
+	clientset := fake.NewSimpleClientset()
+	fw := watch.NewRaceFreeFake()
+	clientset.PrependWatchReactor("pods", func(action fakekubetesting.Action) (bool, watch.Interface, error) {
+		return true, fw, nil
+	})
+
+	pw, err := NewPodWatcher(context.TODO(), 300*time.Millisecond, clientset, metav1.NamespaceDefault)
+	g.Expect(err).To(BeNil())
+	called := false
+	pw.WithTimeoutPodFn(func(msg string) {
+		called = true
+	})
+
+	// the pod keeps changing, the request timeout is a deadline for the whole watch nonetheless
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "pod"}}
+		fw.Add(pod)
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(50 * time.Millisecond):
+				fw.Modify(pod)
+			}
+		}
+	}()
+
+	start := time.Now()
+	_, err = pw.Start(metav1.ListOptions{})
+	g.Expect(err).To(BeNil())
+	g.Expect(called).To(BeTrue())
+	g.Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+}
+
 func Test_PodWatcher_ContextTimeout(t *testing.T) {
 	g := NewWithT(t)
 	ctx := context.TODO()
@@ -152,6 +192,14 @@ func Test_PodWatcherEvents(t *testing.T) {
 	ctx := context.TODO()
 
 	clientset := fake.NewSimpleClientset()
+	// the pod modifications must only happen after the watch is established, otherwise the fake
+	// client does not deliver the events
+	watchCh := make(chan bool)
+	clientset.PrependWatchReactor("pods", func(action fakekubetesting.Action) (bool, watch.Interface, error) {
+		w, err := clientset.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		close(watchCh)
+		return true, w, err
+	})
 
 	pw, err := NewPodWatcher(ctx, math.MaxInt64, clientset, metav1.NamespaceDefault)
 	g.Expect(err).To(BeNil())
@@ -196,6 +244,8 @@ func Test_PodWatcherEvents(t *testing.T) {
 		},
 	}
 
+	<-watchCh
+
 	// making modifications in the pod, making sure all events are exercised, thus the events channel
 	// should be populated
 	podClient := clientset.CoreV1().Pods(metav1.NamespaceDefault)
@@ -233,3 +283,92 @@ func Test_PodWatcherEvents(t *testing.T) {
 	// sometimes it is slow to get these when running go test with race detection
 	g.Eventually(eventsCh, 10*time.Second).Should(Receive(&onPodDeletedFn))
 }
+
+func Test_PodWatcher_ResumeWatch(t *testing.T) {
+	defer func(interval time.Duration) { RetryInterval = interval }(RetryInterval)
+	RetryInterval = 10 * time.Millisecond
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       metav1.NamespaceDefault,
+			Name:            "pod",
+			UID:             "uid",
+			ResourceVersion: "2",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		interrupt func(w *watch.FakeWatcher)
+		relisted  bool
+	}{
+		{
+			name:      "closed",
+			interrupt: func(w *watch.FakeWatcher) { w.Stop() },
+		},
+		{
+			name: "expired",
+			interrupt: func(w *watch.FakeWatcher) {
+				w.Error(&metav1.Status{
+					Status: metav1.StatusFailure,
+					Code:   410,
+					Reason: metav1.StatusReasonExpired,
+				})
+			},
+			relisted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clientset := fake.NewSimpleClientset()
+			// the pod is modified while the watch is interrupted, it should be found by the list
+			clientset.PrependReactor("list", "pods", func(action fakekubetesting.Action) (bool, kruntime.Object, error) {
+				return true, &corev1.PodList{Items: []corev1.Pod{*pod}}, nil
+			})
+			watchers := make(chan *watch.FakeWatcher, 2)
+			clientset.PrependWatchReactor("pods", func(action fakekubetesting.Action) (bool, watch.Interface, error) {
+				w := watch.NewFakeWithChanSize(1, false)
+				watchers <- w
+				return true, w, nil
+			})
+
+			pw, err := NewPodWatcher(context.TODO(), math.MaxInt64, clientset, metav1.NamespaceDefault)
+			g.Expect(err).To(BeNil())
+
+			modified := make(chan string, 2)
+			pw.WithOnPodModifiedFn(func(pod *corev1.Pod) error {
+				modified <- pod.GetResourceVersion()
+				return nil
+			})
+
+			doneCh := make(chan bool)
+			go func() {
+				_, err := pw.Start(metav1.ListOptions{})
+				g.Expect(err).To(BeNil())
+				close(doneCh)
+			}()
+
+			first := <-watchers
+			old := pod.DeepCopy()
+			old.ResourceVersion = "1"
+			first.Modify(old)
+			g.Eventually(modified).Should(Receive(Equal("1")))
+
+			test.interrupt(first)
+			second := <-watchers
+			if test.relisted {
+				g.Eventually(modified).Should(Receive(Equal("2")))
+			}
+
+			// events are delivered by the resumed watch
+			second.Modify(pod)
+			g.Eventually(modified).Should(Receive(Equal("2")))
+
+			pw.Stop()
+			<-doneCh
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package streamer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveExtensions file extensions of the source archives supported.
+var archiveExtensions = []string{".tar", ".tar.gz", ".tgz", ".zip"}
+
+// IsArchive checks if the informed file name has the extension of a supported source archive.
+func IsArchive(fpath string) bool {
+	name := strings.ToLower(fpath)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractArchive extracts the informed tar, gzip compressed tar or zip archive on the target
+// directory, the archive root becomes the directory root.
+func ExtractArchive(fpath, dir string) error {
+	if strings.HasSuffix(strings.ToLower(fpath), ".zip") {
+		return extractZip(fpath, dir)
+	}
+
+	f, err := os.Open(fpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if name := strings.ToLower(fpath); strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("unable to read '%s': %w", fpath, err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+	if err = ExtractTar(r, dir); err != nil {
+		return fmt.Errorf("unable to extract '%s': %w", fpath, err)
+	}
+	return nil
+}
+
+// extractDir the target directory of an archive extraction, with its location resolved, to make
+// sure the entries are not written outside of it through symbolic links extracted before.
+type extractDir struct {
+	dir     string // target directory
+	realDir string // target directory with symbolic links resolved
+}
+
+// newExtractDir instantiate the extractDir for the informed existing directory.
+func newExtractDir(dir string) (*extractDir, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &extractDir{dir: dir, realDir: realDir}, nil
+}
+
+// resolvesWithin checks whether the informed path is located within the target directory, once the
+// symbolic links of its existing part are resolved. The missing part is created as directories.
+func (d *extractDir) resolvesWithin(fpath string) bool {
+	existing := fpath
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return false
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	return err == nil && isWithin(d.realDir, resolved)
+}
+
+// target returns the path of the archive entry on the target directory, entries pointing outside
+// of the target directory are rejected, also when crossing a symbolic link extracted before.
+func (d *extractDir) target(name string) (string, error) {
+	target := filepath.Join(d.dir, filepath.FromSlash(name))
+	if !isWithin(d.dir, target) || !d.resolvesWithin(filepath.Dir(target)) {
+		return "", fmt.Errorf("archive entry %q points outside of %q", name, d.dir)
+	}
+	return target, nil
+}
+
+// replaceable makes sure the target path can be written, an existing symbolic link is removed
+// instead of having the entry written where it points to.
+func replaceable(target string) error {
+	if stat, err := os.Lstat(target); err == nil && stat.Mode()&os.ModeSymlink != 0 {
+		return os.Remove(target)
+	}
+	return nil
+}
+
+// extractFile writes the informed reader contents on the target file.
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := replaceable(target); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractSymlink creates the symbolic link on the target path.
+func extractSymlink(linkname, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := replaceable(target); err != nil {
+		return err
+	}
+	return os.Symlink(linkname, target)
+}
+
+// extractLink creates the hard link on the target path, the linked file must be located within the
+// target directory, also once its symbolic links are resolved.
+func (d *extractDir) extractLink(name, linkname, target string) error {
+	linked, err := d.target(linkname)
+	if err != nil {
+		return err
+	}
+	if !d.resolvesWithin(linked) {
+		return fmt.Errorf("archive entry %q links outside of %q: %q", name, d.dir, linkname)
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err = replaceable(target); err != nil {
+		return err
+	}
+	return os.Link(linked, target)
+}
+
+// ExtractTar extracts directories, regular files, hard and symbolic links from the tar stream onto
+// the target directory, entries pointing outside of the target directory are rejected, also when
+// crossing symbolic links extracted before.
+func ExtractTar(r io.Reader, dir string) error {
+	d, err := newExtractDir(dir)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := d.target(header.Name)
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, mode|0o700)
+		case tar.TypeReg:
+			err = extractFile(tr, target, mode)
+		case tar.TypeLink:
+			err = d.extractLink(header.Name, header.Linkname, target)
+		case tar.TypeSymlink:
+			err = extractSymlink(header.Linkname, target)
+		default:
+			// the pax global header written by "git archive" carries the commit id, devices and
+			// other entry types are not part of a source tree
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip extracts directories, regular files and symbolic links from the zip archive onto the
+// target directory, entries pointing outside of the target directory are rejected, also when
+// crossing symbolic links extracted before.
+func extractZip(fpath, dir string) error {
+	d, err := newExtractDir(dir)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.OpenReader(fpath)
+	if err != nil {
+		return fmt.Errorf("unable to read '%s': %w", fpath, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		target, err := d.target(f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		if mode.IsDir() {
+			if err = os.MkdirAll(target, mode.Perm()|0o700); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		if mode&os.ModeSymlink != 0 {
+			// the symbolic link target is stored as the entry contents
+			var linkname []byte
+			if linkname, err = io.ReadAll(rc); err == nil {
+				err = extractSymlink(string(linkname), target)
+			}
+		} else if mode.IsRegular() {
+			err = extractFile(rc, target, mode.Perm())
+		}
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("unable to extract '%s': %w", fpath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package streamer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+)
+
+// archiveEntry describes an entry written on the test archives.
+type archiveEntry struct {
+	name     string
+	mode     os.FileMode
+	contents string
+}
+
+var archiveEntries = []archiveEntry{
+	{name: "cmd/", mode: os.ModeDir | 0o755},
+	{name: "cmd/main.go", mode: 0o644, contents: "package main"},
+	{name: "run.sh", mode: 0o755, contents: "#!/bin/sh"},
+	{name: "main.go", mode: os.ModeSymlink | 0o777, contents: "cmd/main.go"},
+	{name: "app.log", mode: 0o644, contents: "log"},
+	{name: ".gitignore", mode: 0o644, contents: "*.log\n"},
+}
+
+func writeTarGz(g *gomega.WithT, fpath string, entries []archiveEntry) {
+	f, err := os.Create(fpath)
+	g.Expect(err).To(o.BeNil())
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: int64(e.mode.Perm()), Typeflag: tar.TypeReg}
+		switch {
+		case e.mode.IsDir():
+			header.Typeflag = tar.TypeDir
+		case e.mode&os.ModeSymlink != 0:
+			header.Typeflag, header.Linkname = tar.TypeSymlink, e.contents
+		default:
+			header.Size = int64(len(e.contents))
+		}
+		g.Expect(tw.WriteHeader(header)).To(o.Succeed())
+		if header.Typeflag == tar.TypeReg {
+			_, err = tw.Write([]byte(e.contents))
+			g.Expect(err).To(o.BeNil())
+		}
+	}
+	g.Expect(tw.Close()).To(o.Succeed())
+	g.Expect(gw.Close()).To(o.Succeed())
+}
+
+func writeZip(g *gomega.WithT, fpath string, entries []archiveEntry) {
+	f, err := os.Create(fpath)
+	g.Expect(err).To(o.BeNil())
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		header.SetMode(e.mode)
+		w, err := zw.CreateHeader(header)
+		g.Expect(err).To(o.BeNil())
+		if !e.mode.IsDir() {
+			_, err = w.Write([]byte(e.contents))
+			g.Expect(err).To(o.BeNil())
+		}
+	}
+	g.Expect(zw.Close()).To(o.Succeed())
+}
+
+func Test_Archive(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(IsArchive("/tmp/source.TAR.GZ")).To(o.BeTrue())
+	g.Expect(IsArchive("/tmp/source.tgz")).To(o.BeTrue())
+	g.Expect(IsArchive("/tmp/source.zip")).To(o.BeTrue())
+	g.Expect(IsArchive("/tmp/source.go")).To(o.BeFalse())
+
+	for name, writeFn := range map[string]func(*gomega.WithT, string, []archiveEntry){
+		"source.tar.gz": writeTarGz,
+		"source.zip":    writeZip,
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			fpath := filepath.Join(t.TempDir(), name)
+			writeFn(g, fpath, archiveEntries)
+
+			dir := t.TempDir()
+			g.Expect(ExtractArchive(fpath, dir)).To(o.Succeed())
+
+			stat, err := os.Stat(filepath.Join(dir, "run.sh"))
+			g.Expect(err).To(o.BeNil())
+			g.Expect(stat.Mode().Perm()).To(o.Equal(os.FileMode(0o755)))
+			linkname, err := os.Readlink(filepath.Join(dir, "main.go"))
+			g.Expect(err).To(o.BeNil())
+			g.Expect(linkname).To(o.Equal("cmd/main.go"))
+
+			tarHelper, err := NewTar(dir)
+			g.Expect(err).To(o.BeNil())
+			tarHelper.WithGitConfig(GitConfig{})
+			tarHelper.WithExcludes("run.sh")
+			g.Expect(tarEntries(g, tarHelper)).To(o.Equal(
+				[]string{".gitignore", "cmd/main.go", "main.go"},
+			))
+
+			// entries pointing outside of the target directory are rejected
+			escaping := filepath.Join(t.TempDir(), name)
+			writeFn(g, escaping, []archiveEntry{{name: "../escape.txt", mode: 0o644, contents: "x"}})
+			err = ExtractArchive(escaping, t.TempDir())
+			g.Expect(err).ToNot(o.BeNil())
+			g.Expect(err.Error()).To(o.ContainSubstring("points outside of"))
+
+			// as well as entries crossing a symbolic link extracted before
+			outside := t.TempDir()
+			escaping = filepath.Join(outside, "symlink-"+name)
+			writeFn(g, escaping, []archiveEntry{
+				{name: "link", mode: os.ModeSymlink | 0o777, contents: ".."},
+				{name: "link/escape.txt", mode: 0o644, contents: "x"},
+			})
+			dir = filepath.Join(outside, "dir")
+			g.Expect(os.Mkdir(dir, 0o755)).To(o.Succeed())
+			err = ExtractArchive(escaping, dir)
+			g.Expect(err).ToNot(o.BeNil())
+			g.Expect(err.Error()).To(o.ContainSubstring("points outside of"))
+			g.Expect(filepath.Join(outside, "escape.txt")).ToNot(o.BeAnExistingFile())
+
+			// and entries replacing a symbolic link extracted before
+			escaping = filepath.Join(outside, "replace-"+name)
+			writeFn(g, escaping, []archiveEntry{
+				{name: "link", mode: os.ModeSymlink | 0o777, contents: "../replaced.txt"},
+				{name: "link", mode: 0o644, contents: "x"},
+			})
+			dir = t.TempDir()
+			g.Expect(ExtractArchive(escaping, dir)).To(o.Succeed())
+			g.Expect(filepath.Join(outside, "replaced.txt")).ToNot(o.BeAnExistingFile())
+			stat, err = os.Lstat(filepath.Join(dir, "link"))
+			g.Expect(err).To(o.BeNil())
+			g.Expect(stat.Mode().IsRegular()).To(o.BeTrue())
+		})
+	}
+}
+
+func Test_ExtractTarHardLinks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	extract := func(headers ...*tar.Header) (string, error) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, header := range headers {
+			g.Expect(tw.WriteHeader(header)).To(o.Succeed())
+		}
+		g.Expect(tw.Close()).To(o.Succeed())
+		dir := t.TempDir()
+		return dir, ExtractTar(&buf, dir)
+	}
+
+	// hard links within the target directory are extracted
+	dir, err := extract(
+		&tar.Header{Name: "main.go", Typeflag: tar.TypeReg, Mode: 0o644},
+		&tar.Header{Name: "copy.go", Typeflag: tar.TypeLink, Linkname: "main.go"},
+	)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(filepath.Join(dir, "copy.go")).To(o.BeARegularFile())
+
+	// hard links reaching a file outside of the target directory are rejected, also through
+	// symbolic links extracted before
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	g.Expect(os.WriteFile(outside, []byte("secret"), 0o600)).To(o.Succeed())
+	for _, headers := range [][]*tar.Header{{
+		{Name: "secret.txt", Typeflag: tar.TypeLink, Linkname: "../secret.txt"},
+	}, {
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside},
+		{Name: "secret.txt", Typeflag: tar.TypeLink, Linkname: "link"},
+	}, {
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: filepath.Dir(outside)},
+		{Name: "secret.txt", Typeflag: tar.TypeLink, Linkname: "link/secret.txt"},
+	}} {
+		_, err = extract(headers...)
+		g.Expect(err).ToNot(o.BeNil())
+		g.Expect(err.Error()).To(o.MatchRegexp("(points|links) outside of"))
+	}
+}
@@ -0,0 +1,116 @@
+package streamer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression represents the compression algorithm applied to the data streamed.
+type Compression string
+
+const (
+	// CompressionAuto detects the best compression supported by the target container.
+	CompressionAuto Compression = "auto"
+	// CompressionNone streams the uncompressed tar.
+	CompressionNone Compression = "none"
+	// CompressionGzip compresses the stream using gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses the stream using zstd.
+	CompressionZstd Compression = "zstd"
+)
+
+// Compressions lists all supported compression values.
+var Compressions = []Compression{CompressionAuto, CompressionNone, CompressionGzip, CompressionZstd}
+
+// tarFlag returns the "tar" flag to extract the compressed stream.
+func (c Compression) tarFlag() string {
+	switch c {
+	case CompressionGzip:
+		return "-z"
+	case CompressionZstd:
+		return "--zstd"
+	default:
+		return ""
+	}
+}
+
+// newWriter wraps the informed writer with the compression writer, the returned writer must be
+// closed to flush the compressed data.
+func (c Compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	case CompressionNone, "":
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", c)
+	}
+}
+
+// nopWriteCloser adds a no-op Close method to the writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close implements io.Closer.
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// countingWriter counts the bytes written on the underlying writer.
+type countingWriter struct {
+	w     io.Writer
+	count int64
+}
+
+// Write implements io.Writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// Stats represents the amount of data transferred by the last stream.
+type Stats struct {
+	Compression Compression // compression applied
+	Size        int64       // uncompressed size in bytes
+	Sent        int64       // bytes sent to the target container
+}
+
+// Saved returns the amount of bytes saved by the compression.
+func (s Stats) Saved() int64 {
+	return s.Size - s.Sent
+}
+
+// String shows the stats in human readable form.
+func (s Stats) String() string {
+	if s.Compression == CompressionNone || s.Size == 0 {
+		return fmt.Sprintf("%s sent", HumanBytes(s.Sent))
+	}
+	return fmt.Sprintf("%s sent as %s using %s, %s saved (%.0f%%)",
+		HumanBytes(s.Size),
+		HumanBytes(s.Sent),
+		s.Compression,
+		HumanBytes(s.Saved()),
+		float64(s.Saved())*100/float64(s.Size),
+	)
+}
+
+// HumanBytes formats the informed amount of bytes using binary units.
+func HumanBytes(n int64) string {
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit || v <= -unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
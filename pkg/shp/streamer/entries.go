@@ -0,0 +1,42 @@
+package streamer
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// Totals returns the amount of regular files and their total size.
+func Totals(entries []Entry) (int, int64) {
+	files, size := 0, int64(0)
+	for _, e := range entries {
+		if e.Mode.IsRegular() {
+			files++
+			size += e.Size
+		}
+	}
+	return files, size
+}
+
+// FilesCount formats the amount of files.
+func FilesCount(files int) string {
+	if files == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", files)
+}
+
+// PrintFileList prints the files and symbolic links, with their sizes, followed by the totals.
+func PrintFileList(w io.Writer, entries []Entry) error {
+	for _, e := range entries {
+		switch {
+		case e.Mode.IsRegular():
+			fmt.Fprintf(w, "%10s  %s\n", HumanBytes(e.Size), e.Path)
+		case e.Mode&fs.ModeSymlink != 0:
+			fmt.Fprintf(w, "%10s  %s -> %s\n", "link", e.Path, e.Linkname)
+		}
+	}
+	files, size := Totals(entries)
+	_, err := fmt.Fprintf(w, "Total: %s, %s\n", FilesCount(files), HumanBytes(size))
+	return err
+}
@@ -0,0 +1,85 @@
+package streamer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// GitCommit represents the commit resolved from a Git ref on the local repository.
+type GitCommit struct {
+	SHA   string // full commit SHA
+	Head  bool   // the commit is the one checked out on the working directory
+	Dirty bool   // the working directory has uncommitted or untracked changes, only set with Head
+}
+
+// git runs the informed git command on the repository directory, and returns its output.
+func git(repo string, args ...string) (string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// revParse resolves the informed ref to a commit SHA, a ref which does not exist is reported as such.
+func revParse(repo, ref string) (string, error) {
+	sha, err := git(repo, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", fmt.Errorf("unknown git ref %q", ref)
+	}
+	return sha, err
+}
+
+// ResolveGitRef resolves the informed ref (branch, tag, SHA, HEAD) to the commit on the local
+// repository. When it's the commit checked out, the working directory is inspected for changes.
+func ResolveGitRef(repo, ref string) (*GitCommit, error) {
+	sha, err := revParse(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	commit := &GitCommit{SHA: sha}
+	// a repository without commits has no HEAD, the ref informed can't be the one checked out
+	if head, err := revParse(repo, "HEAD"); err != nil || head != sha {
+		return commit, nil
+	}
+	commit.Head = true
+	status, err := git(repo, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	commit.Dirty = status != ""
+	return commit, nil
+}
+
+// ExportGitTree writes the committed tree of the informed commit on the target directory, using
+// "git archive", thus uncommitted and untracked files are not part of it.
+func ExportGitTree(repo string, commit *GitCommit, dir string) error {
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", "-C", repo, "archive", "--format=tar", commit.SHA)
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	extractErr := ExtractTar(stdout, dir)
+	// draining the remaining output, so git is not blocked writing it
+	_, _ = io.Copy(io.Discard, stdout)
+	if err = cmd.Wait(); err != nil {
+		return fmt.Errorf("git archive: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
@@ -0,0 +1,90 @@
+package streamer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+)
+
+// gitRepo initializes a git repository on a temporary directory with the informed files committed.
+func gitRepo(t *testing.T, g *gomega.WithT, files map[string]string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	repo := t.TempDir()
+	writeFiles(g, repo, files)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=shp", "-c", "user.email=shp@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		_, err := git(repo, args...)
+		g.Expect(err).To(o.BeNil())
+	}
+	return repo
+}
+
+func Test_GitRef(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	repo := gitRepo(t, g, map[string]string{
+		"main.go":        "package main",
+		"pkg/lib.go":     "package pkg",
+		".gitignore":     "*.log\n",
+		"pkg/.gitignore": "",
+	})
+	g.Expect(os.Symlink("main.go", filepath.Join(repo, "link.go"))).To(o.Succeed())
+	_, err := git(repo, "add", "link.go")
+	g.Expect(err).To(o.BeNil())
+	_, err = git(repo, "-c", "user.name=shp", "-c", "user.email=shp@example.com",
+		"commit", "--quiet", "-m", "link")
+	g.Expect(err).To(o.BeNil())
+
+	commit, err := ResolveGitRef(repo, "HEAD")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(commit.SHA).To(o.HaveLen(40))
+	g.Expect(commit.Head).To(o.BeTrue())
+	g.Expect(commit.Dirty).To(o.BeFalse())
+
+	// uncommitted and untracked changes are not part of the exported tree
+	writeFiles(g, repo, map[string]string{"main.go": "package changed", "untracked.go": "package main"})
+	commit, err = ResolveGitRef(repo, "HEAD")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(commit.Dirty).To(o.BeTrue())
+
+	// the working directory changes are not related to other commits
+	previous, err := ResolveGitRef(repo, "HEAD~1")
+	g.Expect(err).To(o.BeNil())
+	g.Expect(previous.SHA).ToNot(o.Equal(commit.SHA))
+	g.Expect(previous.Head).To(o.BeFalse())
+	g.Expect(previous.Dirty).To(o.BeFalse())
+
+	dir := t.TempDir()
+	g.Expect(ExportGitTree(repo, commit, dir)).To(o.Succeed())
+
+	data, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(data)).To(o.Equal("package main"))
+	g.Expect(filepath.Join(dir, "untracked.go")).ToNot(o.BeAnExistingFile())
+	linkname, err := os.Readlink(filepath.Join(dir, "link.go"))
+	g.Expect(err).To(o.BeNil())
+	g.Expect(linkname).To(o.Equal("main.go"))
+
+	tarHelper, err := NewTar(dir)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(tarEntries(g, tarHelper.WithoutGitIgnore())).To(o.Equal(
+		[]string{".gitignore", "link.go", "main.go", "pkg/.gitignore", "pkg/lib.go"},
+	))
+
+	_, err = ResolveGitRef(repo, "does-not-exist")
+	g.Expect(err).To(o.MatchError(`unknown git ref "does-not-exist"`))
+}
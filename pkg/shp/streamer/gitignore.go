@@ -0,0 +1,137 @@
+package streamer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// readPatterns reads the gitignore patterns from the informed file, the patterns are relative to
+// the domain, the directory path components where the file is located. Missing files are skipped.
+func readPatterns(fpath string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns := []gitignore.Pattern{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns, scanner.Err()
+}
+
+// parsePatterns parses the informed patterns relative to the root directory.
+func parsePatterns(lines []string) []gitignore.Pattern {
+	patterns := []gitignore.Pattern{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			patterns = append(patterns, gitignore.ParsePattern(line, nil))
+		}
+	}
+	return patterns
+}
+
+// splitPath splits the slash separated relative path in components, as the gitignore matcher expects.
+func splitPath(rel string) []string {
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+// GitConfig locations of the user git configuration, where the global excludes file is looked up.
+// The zero value means there is no user configuration.
+type GitConfig struct {
+	Home          string // user home directory, holding ".gitconfig", skipped when empty
+	XDGConfigHome string // base directory for user configuration, "$Home/.config" when empty
+}
+
+// UserGitConfig returns the git configuration locations of the current user, like git does, based on
+// the environment. The home directory is left empty, and the error informed, when it can't be
+// determined.
+func UserGitConfig() (GitConfig, error) {
+	home, err := os.UserHomeDir()
+	return GitConfig{Home: home, XDGConfigHome: os.Getenv("XDG_CONFIG_HOME")}, err
+}
+
+// expandHome replaces the leading "~/" by the user home directory, an empty path is returned when
+// the home directory is not known.
+func (c GitConfig) expandHome(fpath string) string {
+	if !strings.HasPrefix(fpath, "~/") {
+		return fpath
+	}
+	if c.Home == "" {
+		return ""
+	}
+	return filepath.Join(c.Home, fpath[2:])
+}
+
+// configExcludesFile reads the "core.excludesFile" option from the informed git configuration file.
+func configExcludesFile(fpath string) (string, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	cfg := config.New()
+	if err = config.NewDecoder(f).Decode(cfg); err != nil {
+		return "", fmt.Errorf("unable to parse %q: %w", fpath, err)
+	}
+	return cfg.Section("core").Options.Get("excludesfile"), nil
+}
+
+// excludesFile finds the global gitignore file, defined by "core.excludesFile" on the user git
+// configuration, or the default "$XDG_CONFIG_HOME/git/ignore" location, like git does. Returns an
+// empty path when there is no such location.
+func (c GitConfig) excludesFile() (string, error) {
+	xdgConfigHome := c.XDGConfigHome
+	if xdgConfigHome == "" && c.Home != "" {
+		xdgConfigHome = filepath.Join(c.Home, ".config")
+	}
+
+	// the configuration on the home directory takes precedence over the XDG location
+	cfgFiles := []string{}
+	excludesFile := ""
+	if xdgConfigHome != "" {
+		excludesFile = filepath.Join(xdgConfigHome, "git", "ignore")
+		cfgFiles = append(cfgFiles, filepath.Join(xdgConfigHome, "git", "config"))
+	}
+	if c.Home != "" {
+		cfgFiles = append(cfgFiles, filepath.Join(c.Home, ".gitconfig"))
+	}
+	for _, cfgFile := range cfgFiles {
+		value, err := configExcludesFile(cfgFile)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			excludesFile = c.expandHome(value)
+		}
+	}
+	return excludesFile, nil
+}
+
+// globalPatterns reads the patterns of the global gitignore file, an empty list when there is none.
+func (c GitConfig) globalPatterns() ([]gitignore.Pattern, error) {
+	excludesFile, err := c.excludesFile()
+	if err != nil || excludesFile == "" {
+		return nil, err
+	}
+	return readPatterns(excludesFile, nil)
+}
@@ -0,0 +1,63 @@
+package streamer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ManifestEntry represents a single regular file streamed, with its relative path, size and the
+// hexadecimal SHA-256 of its contents.
+type ManifestEntry struct {
+	Path   string // path relative to the source directory
+	Size   int64  // file size in bytes
+	SHA256 string // hexadecimal SHA-256 of the file contents
+}
+
+// Manifest lists the files streamed, it's employed to verify the data extracted on the target
+// container is identical to the local source directory.
+type Manifest struct {
+	entries []ManifestEntry
+}
+
+// Add appends a new entry to the manifest.
+func (m *Manifest) Add(entry ManifestEntry) {
+	m.entries = append(m.entries, entry)
+}
+
+// Entries returns the manifest entries sorted by path.
+func (m *Manifest) Entries() []ManifestEntry {
+	entries := append([]ManifestEntry{}, m.entries...)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// String renders the manifest, one entry per line with SHA-256, size and path, sorted by path.
+func (m *Manifest) String() string {
+	var b strings.Builder
+	for _, e := range m.Entries() {
+		fmt.Fprintf(&b, "%s %d %s\n", e.SHA256, e.Size, e.Path)
+	}
+	return b.String()
+}
+
+// validate checks whether the manifest can be verified on the target container, the entries are
+// informed one per line, thus paths containing a newline can't be represented.
+func (m *Manifest) validate() error {
+	for _, e := range m.entries {
+		if strings.ContainsAny(e.Path, "\r\n") {
+			return fmt.Errorf("file name %q contains a line break, it can't be verified", e.Path)
+		}
+	}
+	return nil
+}
+
+// Digest returns the SHA-256 digest of the rendered manifest, prefixed with the algorithm name.
+func (m *Manifest) Digest() string {
+	sum := sha256.Sum256([]byte(m.String()))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package streamer
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+)
+
+func Test_Manifest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	m := &Manifest{}
+	m.Add(ManifestEntry{Path: "b/file.txt", Size: 3, SHA256: "bbb"})
+	m.Add(ManifestEntry{Path: "a file.txt", Size: 1, SHA256: "aaa"})
+
+	g.Expect(m.String()).To(o.Equal("aaa 1 a file.txt\nbbb 3 b/file.txt\n"))
+	g.Expect(m.validate()).To(o.Succeed())
+	g.Expect(m.Digest()).To(o.HavePrefix("sha256:"))
+	g.Expect(m.Digest()).To(o.HaveLen(len("sha256:") + 64))
+
+	// the digest does not depend on the order entries are added
+	other := &Manifest{}
+	other.Add(ManifestEntry{Path: "a file.txt", Size: 1, SHA256: "aaa"})
+	other.Add(ManifestEntry{Path: "b/file.txt", Size: 3, SHA256: "bbb"})
+	g.Expect(other.Digest()).To(o.Equal(m.Digest()))
+
+	// changing the size changes the digest
+	other.Add(ManifestEntry{Path: "c", Size: 0, SHA256: "ccc"})
+	g.Expect(other.Digest()).ToNot(o.Equal(m.Digest()))
+
+	// paths with line breaks can't be informed to the target container
+	other.Add(ManifestEntry{Path: "d\nfile.txt", Size: 0, SHA256: "ddd"})
+	g.Expect(other.validate()).To(o.MatchError(`file name "d\nfile.txt" contains a line break, it can't be verified`))
+}
@@ -0,0 +1,81 @@
+package streamer
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	progressbar "github.com/schollz/progressbar/v3"
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+// ProgressInterval the minimum interval between progress lines, when not writing to a terminal.
+var ProgressInterval = 2 * time.Second
+
+// Progress reports the files and bytes streamed, showing a progress bar on terminals, or plain lines
+// periodically otherwise.
+type Progress struct {
+	out   io.Writer                // output writer
+	bar   *progressbar.ProgressBar // progress bar, when writing to a terminal
+	files int                      // total amount of files to be streamed
+	size  int64                    // total size of the files to be streamed
+
+	lastFiles  int       // amount of files reported on the last update
+	lastSize   int64     // size reported on the last update
+	lastReport time.Time // last time a plain progress line has been written
+}
+
+// line writes a plain progress line.
+func (p *Progress) line() {
+	fmt.Fprintf(p.out, "Streamed %d of %d files, %s of %s\n",
+		p.lastFiles, p.files, HumanBytes(p.lastSize), HumanBytes(p.size))
+	p.lastReport = time.Now()
+}
+
+// Update informs the amount of files, and their total size, streamed so far.
+func (p *Progress) Update(files int, size int64) {
+	p.lastFiles, p.lastSize = files, size
+	if p.bar != nil {
+		p.bar.Describe(fmt.Sprintf("Streaming local source (%d/%d files)...", files, p.files))
+		_ = p.bar.Set64(size)
+		return
+	}
+	if time.Since(p.lastReport) >= ProgressInterval {
+		p.line()
+	}
+}
+
+// Done finishes the progress report.
+func (p *Progress) Done() {
+	if p.bar != nil {
+		_ = p.bar.Finish()
+		return
+	}
+	p.line()
+}
+
+// NewProgress instantiate the progress report for the informed totals, a progress bar is shown when
+// the output is a terminal.
+func NewProgress(out io.Writer, files int, size int64) *Progress {
+	p := &Progress{out: out, files: files, size: size, lastReport: time.Now()}
+	if term.IsTerminal(out) {
+		p.bar = progressbar.NewOptions64(size,
+			progressbar.OptionSetWriter(out),
+			progressbar.OptionEnableColorCodes(true),
+			progressbar.OptionShowBytes(true),
+			progressbar.OptionSetWidth(15),
+			progressbar.OptionSetPredictTime(false),
+			progressbar.OptionSetDescription("Streaming local source..."),
+			progressbar.OptionSetTheme(progressbar.Theme{
+				Saucer:        "[green]=[reset]",
+				SaucerHead:    "[green]>[reset]",
+				SaucerPadding: " ",
+				BarStart:      "[",
+				BarEnd:        "]"}),
+			progressbar.OptionOnCompletion(func() {
+				fmt.Fprintln(out)
+			}),
+		)
+	}
+	return p
+}
@@ -0,0 +1,30 @@
+package streamer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+)
+
+func Test_ProgressPlainLines(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	interval := ProgressInterval
+	defer func() { ProgressInterval = interval }()
+	ProgressInterval = 0
+
+	out := &bytes.Buffer{}
+	p := NewProgress(out, 2, 3072)
+	p.Update(1, 1024)
+	p.Update(2, 3072)
+	p.Done()
+
+	g.Expect(out.String()).To(o.Equal("" +
+		"Streamed 1 of 2 files, 1.0 KiB of 3.0 KiB\n" +
+		"Streamed 2 of 2 files, 3.0 KiB of 3.0 KiB\n" +
+		"Streamed 2 of 2 files, 3.0 KiB of 3.0 KiB\n",
+	))
+}
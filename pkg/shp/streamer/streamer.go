@@ -1,13 +1,18 @@
 package streamer
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
 	"k8s.io/kubectl/pkg/cmd/exec"
 	"k8s.io/kubectl/pkg/util/interrupt"
 )
@@ -18,13 +23,60 @@ type Streamer struct {
 	restConfig     *rest.Config         // rest API client configuration
 	clientset      kubernetes.Interface // kubernetes client
 	remoteExecutor exec.RemoteExecutor  // overwritten during testing
+	compression    Compression          // compression applied on the data streamed
+	verbose        bool                 // shows the files extracted on the POD
+	stats          Stats                // data transferred by the last stream
 }
 
 // WriterFn exposes the writer interface, receives the data to be streamed.
 type WriterFn func(w io.Writer) error
 
 // tarCmd base tar command to be executed on the POD, a target directory should be appended.
-var tarCmd = []string{"tar", "xfv", "-", "-C"}
+var tarCmd = []string{"tar", "xf", "-", "-C"}
+
+// tarVerboseCmd base tar command listing the files extracted, a target directory should be appended.
+var tarVerboseCmd = []string{"tar", "xfv", "-", "-C"}
+
+// detectCompressionCmd command to find out the best compression supported by the "tar" on the POD,
+// it prints the compression name.
+var detectCompressionCmd = []string{"sh", "-c", `if command -v zstd >/dev/null 2>&1 && tar --help 2>&1 | grep -q -- --zstd; then
+	echo zstd
+elif command -v gzip >/dev/null 2>&1; then
+	echo gzip
+else
+	echo none
+fi`}
+
+// verifyCmd command to verify the files extracted on the POD against the manifest, which is informed
+// via stdin, the target directory is the first positional argument. The size of each file is checked
+// first, then the checksums are verified by a single "sha256sum" call.
+var verifyCmd = []string{"sh", "-c", `cd "$0" || exit 1
+failed=0
+checksums=""
+while IFS= read -r line; do
+	sum="${line%% *}"
+	rest="${line#* }"
+	size="${rest%% *}"
+	path="${rest#* }"
+	if [ ! -f "$path" ]; then
+		echo "$path: FAILED open or read"
+		failed=1
+	elif [ $(wc -c <"$path") -ne "$size" ]; then
+		echo "$path: FAILED size"
+		failed=1
+	else
+		checksums="$checksums$sum  $path
+"
+	fi
+done
+if [ -n "$checksums" ]; then
+	printf '%s' "$checksums" | sha256sum -c - || failed=1
+fi
+exit $failed`}
+
+// verifyProbeCmd command to find out whether the tools employed by verifyCmd are available on the
+// POD, it fails otherwise.
+var verifyProbeCmd = []string{"sh", "-c", "command -v sha256sum && command -v wc"}
 
 // doneCmd command to notify the container the data streaming is done, thus the container build
 // process can continue.
@@ -38,9 +90,115 @@ func (s *Streamer) execute(opts *exec.ExecOptions) error {
 	return opts.Run()
 }
 
+// remoteError decorates the error returned by the remote command execution, including its exit
+// code and the standard error output collected, when available.
+func remoteError(command []string, err error, stderr *bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		if msg == "" {
+			return fmt.Errorf("remote %q exited with code %d", command[0], exitErr.ExitStatus())
+		}
+		return fmt.Errorf("remote %q exited with code %d: %s", command[0], exitErr.ExitStatus(), msg)
+	}
+	if msg == "" {
+		return fmt.Errorf("remote %q has failed: %w", command[0], err)
+	}
+	return fmt.Errorf("remote %q has failed: %w: %s", command[0], err, msg)
+}
+
+// SetCompression sets the compression applied on the data streamed, by default the data is not
+// compressed. With CompressionAuto the compression is detected on the target container.
+func (s *Streamer) SetCompression(compression Compression) {
+	s.compression = compression
+}
+
+// SetVerbose sets whether the files extracted on the POD are listed.
+func (s *Streamer) SetVerbose(verbose bool) {
+	s.verbose = verbose
+}
+
+// Stats returns the amount of data transferred by the last stream.
+func (s *Streamer) Stats() Stats {
+	return s.stats
+}
+
+// probe runs the informed command on the target container, returning its standard output.
+func (s *Streamer) probe(target *Target, command []string) (string, error) {
+	output := &bytes.Buffer{}
+	streamOpts := exec.StreamOptions{
+		Namespace:     target.Namespace,
+		PodName:       target.Pod,
+		ContainerName: target.Container,
+		IOStreams: genericclioptions.IOStreams{
+			Out:    output,
+			ErrOut: io.Discard,
+		},
+	}
+	execOpts := &exec.ExecOptions{
+		StreamOptions: streamOpts,
+		Config:        s.restConfig,
+		PodClient:     s.clientset.CoreV1(),
+		Command:       command,
+		Executor:      s.remoteExecutor,
+	}
+	err := s.execute(execOpts)
+	return output.String(), err
+}
+
+// DetectCompression inspects the target container to find out the best compression supported by
+// its "tar", falls back to CompressionNone when it can't be detected.
+func (s *Streamer) DetectCompression(target *Target) Compression {
+	output, err := s.probe(target, detectCompressionCmd)
+	if err != nil {
+		return CompressionNone
+	}
+	switch compression := Compression(strings.TrimSpace(output)); compression {
+	case CompressionGzip, CompressionZstd:
+		return compression
+	default:
+		return CompressionNone
+	}
+}
+
+// compress invokes the writerFn with a writer applying the compression, the amount of data written
+// before and after compression is recorded on the stats.
+func (s *Streamer) compress(compression Compression, w io.Writer, writerFn WriterFn) error {
+	sent := &countingWriter{w: w}
+	cw, err := compression.newWriter(sent)
+	if err != nil {
+		return err
+	}
+	size := &countingWriter{w: cw}
+	if err = writerFn(size); err != nil {
+		cw.Close()
+		return err
+	}
+	if err = cw.Close(); err != nil {
+		return err
+	}
+	s.stats.Size, s.stats.Sent = size.count, sent.count
+	return nil
+}
+
 // Stream the data onto the informed target, and it uses the BaseDir as the path to store the data on
-// the running POD. The writerFn is employed to expose the writer interface to callers.
+// the running POD. The writerFn is employed to expose the writer interface to callers, it always
+// writes the uncompressed data, the compression is applied by the streamer. It returns
+// only when the remote "tar" process has exited, so a nil error confirms the data is extracted on
+// the target directory, otherwise the error carries the remote exit code and standard error.
 func (s *Streamer) Stream(target *Target, writerFn WriterFn) error {
+	compression := s.compression
+	switch compression {
+	case "":
+		compression = CompressionNone
+	case CompressionAuto:
+		compression = s.DetectCompression(target)
+	}
+	s.stats = Stats{Compression: compression}
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -52,14 +210,18 @@ func (s *Streamer) Stream(target *Target, writerFn WriterFn) error {
 	defer close(errCh)
 
 	go func() {
-		defer writer.Close()
-		errCh <- writerFn(writer)
+		err := s.compress(compression, writer, writerFn)
+		// when the writer fails, the error is propagated to the reader end, interrupting the stream
+		// instead of sending a truncated payload
+		writer.CloseWithError(err)
+		errCh <- err
 		wg.Done()
 	}()
 
 	// defines the target pod using namespace and pod name, and wires up the local stdin with the
 	// pipe reader interface, therefore all data written on the writer interface will be redirected
-	// to the pod
+	// to the pod. The standard error is collected to be reported back
+	stderr := &bytes.Buffer{}
 	streamOpts := exec.StreamOptions{
 		Namespace:     target.Namespace,
 		PodName:       target.Pod,
@@ -68,29 +230,90 @@ func (s *Streamer) Stream(target *Target, writerFn WriterFn) error {
 		IOStreams: genericclioptions.IOStreams{
 			In:     reader,
 			Out:    os.Stdout,
-			ErrOut: os.Stderr,
+			ErrOut: io.MultiWriter(os.Stderr, stderr),
 		},
 	}
 	// creates the equivalent of "kubectl exec" structure, plus the stdin redirect, and then runs the
 	// predefined ar command on the pod to receive the data stream
+	command := append([]string{}, tarCmd...)
+	if s.verbose {
+		command = append([]string{}, tarVerboseCmd...)
+	}
+	command = append(command, target.BaseDir)
+	if flag := compression.tarFlag(); flag != "" {
+		command = append(command, flag)
+	}
 	execOpts := &exec.ExecOptions{
 		StreamOptions: streamOpts,
 		Config:        s.restConfig,
 		PodClient:     s.clientset.CoreV1(),
-		Command:       append(tarCmd, target.BaseDir),
+		Command:       command,
 		Executor:      s.remoteExecutor,
 	}
-	if err := s.execute(execOpts); err != nil {
+	execErr := s.execute(execOpts)
+	// closing the reader end, when the remote command exits early the writerFn is not blocked
+	reader.Close()
+
+	// blocking the execution, waiting for writerFn to return either error or nil. When the remote
+	// command exits early the writerFn fails on the closed pipe, the remote error is reported instead
+	wg.Wait()
+	if err := <-errCh; err != nil && !errors.Is(err, io.ErrClosedPipe) {
 		return err
 	}
+	return remoteError(command, execErr, stderr)
+}
 
-	// blocking the execution, waiting for writerFn to return either error or nil
-	wg.Wait()
-	return <-errCh
+// CanVerify inspects the target container to find out whether the tools employed by Verify, "sh",
+// "sha256sum" and "wc", are available.
+func (s *Streamer) CanVerify(target *Target) bool {
+	_, err := s.probe(target, verifyProbeCmd)
+	return err == nil
+}
+
+// Verify checks the data extracted on the target's BaseDir against the informed manifest, by running
+// "sha256sum" on the target container. Returns error when any file is missing or differs.
+func (s *Streamer) Verify(target *Target, manifest *Manifest) error {
+	if err := manifest.validate(); err != nil {
+		return fmt.Errorf("manifest verification failed: %w", err)
+	}
+	// the files failing the check are reported on stdout, both outputs are collected
+	output := &bytes.Buffer{}
+	streamOpts := exec.StreamOptions{
+		Namespace:     target.Namespace,
+		PodName:       target.Pod,
+		ContainerName: target.Container,
+		Stdin:         true,
+		IOStreams: genericclioptions.IOStreams{
+			In:     strings.NewReader(manifest.String()),
+			Out:    output,
+			ErrOut: output,
+		},
+	}
+	command := append(append([]string{}, verifyCmd...), target.BaseDir)
+	execOpts := &exec.ExecOptions{
+		StreamOptions: streamOpts,
+		Config:        s.restConfig,
+		PodClient:     s.clientset.CoreV1(),
+		Command:       command,
+		Executor:      s.remoteExecutor,
+	}
+	err := s.execute(execOpts)
+	if err == nil {
+		return nil
+	}
+	// only the files failing the check are relevant
+	failures := &bytes.Buffer{}
+	for _, line := range strings.SplitAfter(output.String(), "\n") {
+		if !strings.HasSuffix(strings.TrimSpace(line), ": OK") {
+			failures.WriteString(line)
+		}
+	}
+	return fmt.Errorf("manifest verification failed: %w", remoteError([]string{"sha256sum"}, err, failures))
 }
 
 // Done uses "kubectl exec" to run an command on target container, notifying the upload is done.
 func (s *Streamer) Done(target *Target) error {
+	stderr := &bytes.Buffer{}
 	streamOpts := exec.StreamOptions{
 		Namespace:       target.Namespace,
 		PodName:         target.Pod,
@@ -98,7 +321,7 @@ func (s *Streamer) Done(target *Target) error {
 		InterruptParent: &interrupt.Handler{},
 		IOStreams: genericclioptions.IOStreams{
 			Out:    os.Stdout,
-			ErrOut: os.Stderr,
+			ErrOut: io.MultiWriter(os.Stderr, stderr),
 		},
 	}
 	execOpts := &exec.ExecOptions{
@@ -108,7 +331,7 @@ func (s *Streamer) Done(target *Target) error {
 		Command:       doneCmd,
 		Executor:      s.remoteExecutor,
 	}
-	return s.execute(execOpts)
+	return remoteError(doneCmd, s.execute(execOpts), stderr)
 }
 
 // NewStreamer instantiate Streamer.
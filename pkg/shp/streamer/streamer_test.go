@@ -1,13 +1,18 @@
 package streamer
 
 import (
+	"compress/gzip"
+	"errors"
 	"io"
+	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/onsi/gomega"
 	"github.com/shipwright-io/cli/test/mock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
 
 	o "github.com/onsi/gomega"
 )
@@ -46,11 +51,184 @@ func Test_Streamer(t *testing.T) {
 		return err
 	})
 	g.Expect(err).To(o.BeNil())
-	g.Expect(re.Command()).To(o.Equal([]string{"tar", "xfv", "-", "-C", "/"}))
+	g.Expect(re.Command()).To(o.Equal([]string{"tar", "xf", "-", "-C", "/"}))
 	g.Expect(re.Stdin()).To(o.Equal(stdin))
 
+	// in verbose mode the remote tar lists the files extracted
+	s.SetVerbose(true)
+	err = s.Stream(targetPod, func(w io.Writer) error {
+		_, err := w.Write([]byte(stdin))
+		return err
+	})
+	g.Expect(err).To(o.BeNil())
+	g.Expect(re.Command()).To(o.Equal([]string{"tar", "xfv", "-", "-C", "/"}))
+	s.SetVerbose(false)
+
+	// verifying the manifest on the target pod, the manifest is informed via stdin
+	g.Expect(s.CanVerify(targetPod)).To(o.BeTrue())
+	g.Expect(re.Command()).To(o.Equal(verifyProbeCmd))
+	manifest := &Manifest{}
+	manifest.Add(ManifestEntry{Path: "file.txt", Size: 1, SHA256: "aaa"})
+	err = s.Verify(targetPod, manifest)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(re.Command()).To(o.Equal(append(append([]string{}, verifyCmd...), "/")))
+	g.Expect(re.Stdin()).To(o.HaveSuffix("aaa 1 file.txt\n"))
+
 	// calling out "done" command on target pod, and making sure the command informed is expected
 	err = s.Done(targetPod)
 	g.Expect(err).To(o.BeNil())
 	g.Expect(re.Command()).To(o.Equal([]string{"waiter", "done"}))
 }
+
+func Test_StreamerRemoteErrors(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	podName := "pod"
+	f := mock.NewFakeClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      podName,
+		},
+	})
+	s := NewStreamer(f.RESTConfig(), f.Clientset())
+
+	targetPod := &Target{
+		Namespace: metav1.NamespaceDefault,
+		Pod:       podName,
+		Container: "container",
+		BaseDir:   "/",
+	}
+	writerFn := func(w io.Writer) error {
+		_, err := w.Write([]byte("standard input"))
+		return err
+	}
+
+	// the remote tar exit code and standard error are reported back
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(utilexec.CodeExitError{
+		Err:  errors.New("command terminated with exit code 2"),
+		Code: 2,
+	}).WithStderr("tar: /: Cannot open: Permission denied\n")
+	err := s.Stream(targetPod, writerFn)
+	g.Expect(err).ToNot(o.BeNil())
+	g.Expect(err.Error()).To(o.Equal(
+		`remote "tar" exited with code 2: tar: /: Cannot open: Permission denied`,
+	))
+
+	// the remote tar exiting before reading all the data is reported as well, instead of the writer
+	// failing on the closed pipe
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(utilexec.CodeExitError{
+		Err:  errors.New("command terminated with exit code 2"),
+		Code: 2,
+	}).WithStderr("tar: file.txt: Cannot write: No space left on device\n").WithStdinLimit(10)
+	err = s.Stream(targetPod, func(w io.Writer) error {
+		_, err := w.Write([]byte(strings.Repeat("x", 1024*1024)))
+		return err
+	})
+	g.Expect(err).ToNot(o.BeNil())
+	g.Expect(err.Error()).To(o.Equal(
+		`remote "tar" exited with code 2: tar: file.txt: Cannot write: No space left on device`,
+	))
+
+	// errors on the writer function take precedence, the stream is interrupted
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(nil)
+	writerErr := errors.New("writer error")
+	err = s.Stream(targetPod, func(w io.Writer) error {
+		return writerErr
+	})
+	g.Expect(err).To(o.Equal(writerErr))
+
+	// files failing the verification are reported
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(utilexec.CodeExitError{
+		Err:  errors.New("command terminated with exit code 1"),
+		Code: 1,
+	}).WithStdout("file.txt: FAILED size\nother.txt: OK\n")
+	manifest := &Manifest{}
+	manifest.Add(ManifestEntry{Path: "file.txt", Size: 1, SHA256: "aaa"})
+	manifest.Add(ManifestEntry{Path: "other.txt", Size: 1, SHA256: "bbb"})
+	err = s.Verify(targetPod, manifest)
+	g.Expect(err).ToNot(o.BeNil())
+	g.Expect(err.Error()).To(o.Equal(
+		`manifest verification failed: remote "sha256sum" exited with code 1: file.txt: FAILED size`,
+	))
+
+	// file names which can't be informed to the target container are rejected
+	manifest.Add(ManifestEntry{Path: "new\nline.txt", Size: 1, SHA256: "ccc"})
+	err = s.Verify(targetPod, manifest)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("contains a line break")))
+
+	// containers without the tools to verify the data are detected
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(utilexec.CodeExitError{
+		Err:  errors.New("command terminated with exit code 1"),
+		Code: 1,
+	})
+	g.Expect(s.CanVerify(targetPod)).To(o.BeFalse())
+
+	// "waiter done" failures are reported as well
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(errors.New("connection refused"))
+	err = s.Done(targetPod)
+	g.Expect(err).ToNot(o.BeNil())
+	g.Expect(err.Error()).To(o.Equal(`remote "waiter" has failed: connection refused`))
+}
+
+func Test_StreamerCompression(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	podName := "pod"
+	f := mock.NewFakeClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      podName,
+		},
+	})
+	s := NewStreamer(f.RESTConfig(), f.Clientset())
+
+	targetPod := &Target{
+		Namespace: metav1.NamespaceDefault,
+		Pod:       podName,
+		Container: "container",
+		BaseDir:   "/",
+	}
+	stdin := strings.Repeat("standard input ", 1024)
+	writerFn := func(w io.Writer) error {
+		_, err := w.Write([]byte(stdin))
+		return err
+	}
+
+	// gzip compressed stream, the remote tar is instructed to decompress it
+	re := mock.NewFakeRemoteExecutor(nil)
+	s.remoteExecutor = re
+	s.SetCompression(CompressionGzip)
+	g.Expect(s.Stream(targetPod, writerFn)).To(o.Succeed())
+	g.Expect(re.Command()).To(o.Equal([]string{"tar", "xf", "-", "-C", "/", "-z"}))
+
+	gz, err := gzip.NewReader(strings.NewReader(re.Stdin()))
+	g.Expect(err).To(o.BeNil())
+	data, err := io.ReadAll(gz)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(data)).To(o.Equal(stdin))
+
+	stats := s.Stats()
+	g.Expect(stats.Compression).To(o.Equal(CompressionGzip))
+	g.Expect(stats.Size).To(o.Equal(int64(len(stdin))))
+	g.Expect(stats.Sent).To(o.Equal(int64(len(re.Stdin()))))
+	g.Expect(stats.Saved() > 0).To(o.BeTrue())
+	g.Expect(stats.String()).To(o.ContainSubstring("15.0 KiB sent as"))
+
+	// automatic detection, the target container supports zstd
+	re = mock.NewFakeRemoteExecutor(nil).WithStdout("zstd\n")
+	s.remoteExecutor = re
+	s.SetCompression(CompressionAuto)
+	g.Expect(s.Stream(targetPod, writerFn)).To(o.Succeed())
+	g.Expect(re.Command()).To(o.Equal([]string{"tar", "xf", "-", "-C", "/", "--zstd"}))
+
+	zr, err := zstd.NewReader(strings.NewReader(re.Stdin()))
+	g.Expect(err).To(o.BeNil())
+	defer zr.Close()
+	data, err = io.ReadAll(zr)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(string(data)).To(o.Equal(stdin))
+
+	// detection failures fall back to the uncompressed stream
+	s.remoteExecutor = mock.NewFakeRemoteExecutor(errors.New("sh: not found"))
+	g.Expect(s.DetectCompression(targetPod)).To(o.Equal(CompressionNone))
+}
@@ -2,61 +2,366 @@ package streamer
 
 import (
 	"archive/tar"
+	"fmt"
 	"io"
 	"io/fs"
+	"log"
 	"os"
-	"path"
 	"path/filepath"
-	"strings"
+	"time"
 
-	ignore "github.com/sabhiram/go-gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
+// ShpIgnoreFile file with gitignore syntax listing the entries to skip on upload.
+const ShpIgnoreFile = ".shpignore"
+
+// gitIgnoreFile file with the git ignore patterns, loaded for each directory.
+const gitIgnoreFile = ".gitignore"
+
 // Tar helper to create a tar instance based on a source directory, skipping entries that are not
-// desired like `.git` directory and entries in `.gitignore` file.
+// desired like `.git` directory and entries ignored by git, following the same semantics: the global
+// excludes file, `.git/info/exclude` and `.gitignore` files in any directory. Entries in `.shpignore`
+// or matching the exclude patterns are skipped as well, while entries matching the include patterns
+// are never skipped. Directories, including empty ones, and symbolic links are written with their
+// original mode, symbolic links pointing outside of the base directory are rejected.
 type Tar struct {
-	src       string            // base directory
-	gitIgnore *ignore.GitIgnore // matcher for git ignored files
+	src          string              // base directory
+	noGitIgnore  bool                // disables the git ignore patterns
+	dereference  bool                // writes the symbolic link target contents instead of the link
+	reproducible bool                // normalizes the entries, producing the same tar for the same contents
+	gitConfig    *GitConfig          // user git configuration locations, the current user's when not set
+	globalIgnore []gitignore.Pattern // global git ignore patterns, loaded on the first walk
+	globalLoaded bool                // global git ignore patterns have been loaded
+	gitIgnore    []gitignore.Pattern // repository git ignore patterns
+	shpIgnore    []gitignore.Pattern // shp ignore patterns
+	excludes     []gitignore.Pattern // user informed exclude patterns
+	includes     []gitignore.Pattern // user informed include patterns
+	manifest     *Manifest           // files written on the last tar created
+	progressFn   ProgressFn          // reports the files written
+}
+
+// ProgressFn receives the amount of files, and their total size in bytes, written so far.
+type ProgressFn func(files int, size int64)
+
+// WithProgressFn sets the function to report the progress while the tar is created.
+func (t *Tar) WithProgressFn(fn ProgressFn) *Tar {
+	t.progressFn = fn
+	return t
+}
+
+// WithReproducible normalizes the entries written, resetting their modification time, owner and
+// group, thus the same entries and contents always produce the same tar.
+func (t *Tar) WithReproducible() *Tar {
+	t.reproducible = true
+	return t
+}
+
+// WithExcludes sets the patterns, using gitignore syntax, of the entries to be skipped.
+func (t *Tar) WithExcludes(patterns ...string) *Tar {
+	t.excludes = parsePatterns(patterns)
+	return t
+}
+
+// WithIncludes sets the patterns, using gitignore syntax, of the entries to be included even when
+// ignored or excluded.
+func (t *Tar) WithIncludes(patterns ...string) *Tar {
+	t.includes = parsePatterns(patterns)
+	return t
+}
+
+// WithoutGitIgnore disables the git ignore patterns, entries ignored by git are included.
+func (t *Tar) WithoutGitIgnore() *Tar {
+	t.noGitIgnore = true
+	return t
+}
+
+// WithGitConfig sets the locations of the user git configuration, where the global git ignore file
+// is looked up, instead of the current user's.
+func (t *Tar) WithGitConfig(cfg GitConfig) *Tar {
+	t.gitConfig = &cfg
+	t.globalLoaded = false
+	return t
+}
+
+// WithDereferenceSymlinks writes the contents of the file a symbolic link points to, instead of the
+// link itself, for consumers that don't support symbolic links. Symbolic links to directories are
+// rejected in this mode.
+func (t *Tar) WithDereferenceSymlinks() *Tar {
+	t.dereference = true
+	return t
+}
+
+// Src returns the source directory.
+func (t *Tar) Src() string {
+	return t.src
 }
 
-// skipPath inspect each path and makes sure it skips files the tar helper can't handle.
-func (t *Tar) skipPath(fpath string, stat fs.FileInfo) bool {
-	if !stat.Mode().IsRegular() {
+// skipPath inspect each path, and checks if it should be skipped based on the ignore patterns. The
+// git ignore patterns are informed, as they change during the walk.
+func (t *Tar) skipPath(rel string, isDir bool, gitIgnore []gitignore.Pattern) bool {
+	if rel == ".git" {
 		return true
 	}
-	if strings.HasPrefix(fpath, path.Join(t.src, ".git")) {
+	path := splitPath(rel)
+	if gitignore.NewMatcher(t.includes).Match(path, isDir) {
+		return false
+	}
+	if !t.noGitIgnore && gitignore.NewMatcher(gitIgnore).Match(path, isDir) {
 		return true
 	}
-	if t.gitIgnore == nil {
-		return false
+	return gitignore.NewMatcher(t.shpIgnore).Match(path, isDir) ||
+		gitignore.NewMatcher(t.excludes).Match(path, isDir)
+}
+
+// resolveSymlink reads the informed symbolic link, and makes sure it points inside the base
+// directory, also when following a chain of links. Returns the link target, relative to the link
+// location when the original target is an absolute path.
+func (t *Tar) resolveSymlink(fpath string) (string, error) {
+	linkname, err := os.Readlink(fpath)
+	if err != nil {
+		return "", err
+	}
+	target := linkname
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(fpath), target)
+	}
+	target = filepath.Clean(target)
+
+	escapesErr := fmt.Errorf("symbolic link %q points outside of %q: %q", fpath, t.src, linkname)
+	if !isWithin(t.src, target) {
+		return "", escapesErr
+	}
+	// when the link target exists, all links in between are resolved to check the final location
+	if real, err := filepath.EvalSymlinks(fpath); err == nil {
+		realSrc, err := filepath.EvalSymlinks(t.src)
+		if err != nil {
+			return "", err
+		}
+		if !isWithin(realSrc, real) {
+			return "", escapesErr
+		}
+	}
+
+	if filepath.IsAbs(linkname) {
+		return filepath.Rel(filepath.Dir(fpath), target)
 	}
-	return t.gitIgnore.MatchesPath(fpath)
+	return linkname, nil
 }
 
-// Create the actual tar by inspecting all files in source path, skipping some.
-func (t *Tar) Create(w io.Writer) error {
-	tw := tar.NewWriter(w)
-	filepath.Walk(t.src, func(fpath string, stat fs.FileInfo, err error) error {
+// writeSymlink writes the symbolic link entry, or the file it points to when dereferencing.
+func (t *Tar) writeSymlink(tw tarWriter, fpath string, stat fs.FileInfo) error {
+	linkname, err := t.resolveSymlink(fpath)
+	if err != nil {
+		return err
+	}
+	if !t.dereference {
+		return writeSymlinkToTar(tw, t.src, fpath, linkname, stat)
+	}
+
+	targetStat, err := os.Stat(fpath)
+	if err != nil {
+		return fmt.Errorf("unable to dereference symbolic link %q: %w", fpath, err)
+	}
+	if !targetStat.Mode().IsRegular() {
+		return fmt.Errorf("symbolic link %q must point to a regular file: %q", fpath, linkname)
+	}
+	return writeFileToTar(tw, t.src, fpath, targetStat, t.manifest)
+}
+
+// Manifest returns the manifest of the files written by the last Create call.
+func (t *Tar) Manifest() *Manifest {
+	return t.manifest
+}
+
+// visitFn handles an entry of the source directory not skipped, either a directory, a regular file
+// or a symbolic link. The path relative to the source directory is informed.
+type visitFn func(fpath, rel string, stat fs.FileInfo) error
+
+// walk inspects all entries in source path, skipping some, and visits the remaining ones in
+// lexical order, directories before their contents.
+func (t *Tar) walk(visit visitFn) error {
+	gitIgnore := append(t.globalGitIgnore(), t.gitIgnore...)
+	return filepath.Walk(t.src, func(fpath string, stat fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if t.skipPath(fpath, stat) {
+		rel, err := filepath.Rel(t.src, fpath)
+		if err != nil {
+			return err
+		}
+
+		if stat.IsDir() {
+			switch {
+			case rel == ".":
+				rel = ""
+			case t.skipPath(rel, true, gitIgnore):
+				// when include patterns are informed, entries may be included from skipped
+				// directories, so they are inspected as well
+				if len(t.includes) == 0 || rel == ".git" {
+					return filepath.SkipDir
+				}
+			default:
+				if err := visit(fpath, rel, stat); err != nil {
+					return err
+				}
+			}
+			// the patterns of a nested ".gitignore" are relative to its directory, and take
+			// precedence over the patterns found on parent directories
+			if !t.noGitIgnore {
+				var domain []string
+				if rel != "" {
+					domain = splitPath(rel)
+				}
+				patterns, err := readPatterns(filepath.Join(fpath, gitIgnoreFile), domain)
+				if err != nil {
+					return err
+				}
+				gitIgnore = append(gitIgnore, patterns...)
+			}
+			return nil
+		}
+
+		if t.skipPath(rel, false, gitIgnore) {
 			return nil
 		}
-		return writeFileToTar(tw, t.src, fpath, stat)
+		if stat.Mode().IsRegular() || stat.Mode()&fs.ModeSymlink != 0 {
+			return visit(fpath, rel, stat)
+		}
+		// devices, sockets and named pipes are not part of the source code
+		return nil
 	})
-	return tw.Close()
 }
 
-// bootstrap instantiate git-ignore helper.
-func (t *Tar) bootstrap() error {
-	gitIgnorePath := path.Join(t.src, ".gitignore")
-	_, err := os.Stat(gitIgnorePath)
+// Entry represents an entry of the source directory that would be written by Create.
+type Entry struct {
+	Path     string      // slash separated path relative to the source directory
+	Mode     fs.FileMode // file mode and type
+	Size     int64       // size in bytes, for regular files
+	Linkname string      // symbolic link target
+	ModTime  time.Time   // modification time
+}
+
+// List inspects the source directory, without reading the files, and returns the entries that
+// would be written by Create, in the same order.
+func (t *Tar) List() ([]Entry, error) {
+	entries := []Entry{}
+	err := t.walk(func(fpath, rel string, stat fs.FileInfo) error {
+		entry := Entry{Path: filepath.ToSlash(rel), Mode: stat.Mode(), ModTime: stat.ModTime()}
+		switch {
+		case stat.Mode().IsRegular():
+			entry.Size = stat.Size()
+		case stat.Mode()&fs.ModeSymlink != 0:
+			linkname, err := t.resolveSymlink(fpath)
+			if err != nil {
+				return err
+			}
+			entry.Linkname = filepath.ToSlash(linkname)
+			if t.dereference {
+				target, err := os.Stat(fpath)
+				if err != nil {
+					return fmt.Errorf("unable to dereference symbolic link %q: %w", fpath, err)
+				}
+				entry.Mode, entry.Size, entry.ModTime = target.Mode(), target.Size(), target.ModTime()
+			}
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// Scan inspects the source directory, without reading the files, and returns the amount of regular
+// files and their total size in bytes, that would be written by Create.
+func (t *Tar) Scan() (int, int64, error) {
+	entries, err := t.List()
+	if err != nil {
+		return 0, 0, err
+	}
+	files, size := Totals(entries)
+	return files, size, nil
+}
+
+// Create the actual tar by inspecting all files in source path, skipping some. Files that can't be
+// read interrupt the tar creation with error, instead of being left behind.
+func (t *Tar) Create(w io.Writer) error {
+	t.manifest = &Manifest{}
+	files, size := 0, int64(0)
+
+	tarball := tar.NewWriter(w)
+	var tw tarWriter = tarball
+	if t.reproducible {
+		tw = &reproducibleWriter{Writer: tarball}
+	}
+	err := t.walk(func(fpath, _ string, stat fs.FileInfo) error {
+		var err error
+		switch {
+		case stat.IsDir():
+			return writeDirToTar(tw, t.src, fpath, stat)
+		case stat.Mode().IsRegular():
+			err = writeFileToTar(tw, t.src, fpath, stat, t.manifest)
+		default:
+			err = t.writeSymlink(tw, fpath, stat)
+		}
+		if err != nil {
+			return err
+		}
+
+		// reporting progress when a new file has been recorded on the manifest
+		if entries := t.manifest.entries; len(entries) > files {
+			files = len(entries)
+			size += entries[files-1].Size
+			if t.progressFn != nil {
+				t.progressFn(files, size)
+			}
+		}
+		return nil
+	})
 	if err != nil {
+		return err
+	}
+	return tarball.Close()
+}
+
+// globalGitIgnore returns the patterns of the global git ignore file, found on the user git
+// configuration, loaded once. The upload does not depend on them, thus a missing home directory or
+// an invalid configuration only produces a warning, and the global patterns are skipped.
+func (t *Tar) globalGitIgnore() []gitignore.Pattern {
+	if t.noGitIgnore {
 		return nil
 	}
+	if t.globalLoaded {
+		return append([]gitignore.Pattern{}, t.globalIgnore...)
+	}
+	t.globalLoaded = true
+	t.globalIgnore = nil
 
-	t.gitIgnore, err = ignore.CompileIgnoreFile(gitIgnorePath)
+	cfg := GitConfig{}
+	if t.gitConfig != nil {
+		cfg = *t.gitConfig
+	} else {
+		var err error
+		if cfg, err = UserGitConfig(); err != nil {
+			log.Printf("WARNING: Unable to find the user git configuration: %v", err)
+		}
+	}
+	patterns, err := cfg.globalPatterns()
+	if err != nil {
+		log.Printf("WARNING: Skipping the global git ignore patterns: %v", err)
+		return nil
+	}
+	t.globalIgnore = patterns
+	return append([]gitignore.Pattern{}, t.globalIgnore...)
+}
+
+// bootstrap loads the repository exclude file and the shp-ignore patterns.
+func (t *Tar) bootstrap() error {
+	var err error
+	t.gitIgnore, err = readPatterns(filepath.Join(t.src, ".git", "info", "exclude"), nil)
+	if err != nil {
+		return err
+	}
+	t.shpIgnore, err = readPatterns(filepath.Join(t.src, ShpIgnoreFile), nil)
 	return err
 }
 
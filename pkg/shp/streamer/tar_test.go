@@ -2,9 +2,14 @@ package streamer
 
 import (
 	"archive/tar"
+	"bytes"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 
@@ -17,6 +22,11 @@ func Test_Tar(t *testing.T) {
 	tarHelper, err := NewTar("../../..")
 	g.Expect(err).To(o.BeNil())
 
+	progressFiles, progressSize := 0, int64(0)
+	tarHelper.WithProgressFn(func(files int, size int64) {
+		progressFiles, progressSize = files, size
+	})
+
 	reader, writer := io.Pipe()
 	defer reader.Close()
 	defer writer.Close()
@@ -36,7 +46,9 @@ func Test_Tar(t *testing.T) {
 			}
 			g.Expect(err).To(o.BeNil())
 		}
-		counter++
+		if header.Typeflag == tar.TypeReg {
+			counter++
+		}
 		name := header.Name
 
 		// making sure that undesired entries are not present on the list of files caputured by the
@@ -45,4 +57,237 @@ func Test_Tar(t *testing.T) {
 		g.Expect(strings.HasPrefix(name, "_output/")).To(o.BeFalse())
 	}
 	g.Expect(counter > 10).To(o.BeTrue())
+
+	// all entries written are recorded on the manifest, with the contents checksum
+	entries := tarHelper.Manifest().Entries()
+	g.Expect(len(entries)).To(o.Equal(counter))
+	size := int64(0)
+	for _, e := range entries {
+		g.Expect(e.SHA256).To(o.HaveLen(64))
+		size += e.Size
+	}
+
+	// the progress and the scan report the same totals
+	g.Expect(progressFiles).To(o.Equal(counter))
+	g.Expect(progressSize).To(o.Equal(size))
+	scanFiles, scanSize, err := tarHelper.Scan()
+	g.Expect(err).To(o.BeNil())
+	g.Expect(scanFiles).To(o.Equal(counter))
+	g.Expect(scanSize).To(o.Equal(size))
+}
+
+// tarHeaders creates the tar and returns its headers by name.
+func tarHeaders(g *gomega.WithT, tarHelper *Tar) map[string]*tar.Header {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(tarHelper.Create(writer))
+	}()
+
+	headers := map[string]*tar.Header{}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).To(o.BeNil())
+		headers[header.Name] = header
+	}
+	return headers
+}
+
+// tarEntries creates the tar and returns the sorted names of its entries, except directories.
+func tarEntries(g *gomega.WithT, tarHelper *Tar) []string {
+	names := []string{}
+	for name, header := range tarHeaders(g, tarHelper) {
+		if header.Typeflag != tar.TypeDir {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeFiles writes the informed files, relative to the source directory.
+func writeFiles(g *gomega.WithT, src string, files map[string]string) {
+	for name, content := range files {
+		fpath := filepath.Join(src, name)
+		g.Expect(os.MkdirAll(filepath.Dir(fpath), 0o755)).To(o.Succeed())
+		g.Expect(os.WriteFile(fpath, []byte(content), 0o644)).To(o.Succeed())
+	}
+}
+
+func Test_TarIgnoreRules(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	src := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":          "ref: refs/heads/main",
+		".github/ci.yaml":    "on: push",
+		".gitignore":         "/build/\n*.log\n",
+		ShpIgnoreFile:        "docs/\n",
+		"main.go":            "package main",
+		"app.log":            "log",
+		"build/generated.go": "package build",
+		"docs/index.md":      "# docs",
+		"testdata/large.bin": "binary",
+	}
+	writeFiles(g, src, files)
+
+	tarHelper, err := NewTar(src)
+	g.Expect(err).To(o.BeNil())
+	tarHelper.WithGitConfig(GitConfig{})
+	g.Expect(tarEntries(g, tarHelper)).To(o.Equal([]string{
+		".github/ci.yaml",
+		".gitignore",
+		ShpIgnoreFile,
+		"main.go",
+		"testdata/large.bin",
+	}))
+
+	// exclude patterns skip committed entries, include patterns bring back ignored entries
+	tarHelper.WithExcludes("testdata/", ".github").WithIncludes("build/generated.go", "docs/")
+	g.Expect(tarEntries(g, tarHelper)).To(o.Equal([]string{
+		".gitignore",
+		ShpIgnoreFile,
+		"build/generated.go",
+		"docs/index.md",
+		"main.go",
+	}))
+}
+
+func Test_TarGitIgnoreSemantics(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// global excludes file informed on the user git configuration
+	home := t.TempDir()
+	writeFiles(g, home, map[string]string{
+		".gitconfig":        "[core]\n\texcludesFile = ~/.gitignore_global\n",
+		".gitignore_global": ".DS_Store\n",
+	})
+
+	src := t.TempDir()
+	writeFiles(g, src, map[string]string{
+		".git/info/exclude":             "*.swp\n",
+		".gitignore":                    "*.tmp\n",
+		".DS_Store":                     "",
+		"main.go":                       "package main",
+		"main.go.swp":                   "",
+		"web/.gitignore":                "node_modules/\n/dist\n!keep.tmp\n",
+		"web/index.js":                  "",
+		"web/keep.tmp":                  "",
+		"web/other.tmp":                 "",
+		"web/dist/bundle.js":            "",
+		"web/node_modules/pkg/index.js": "",
+		"web/src/dist/component.js":     "",
+		"api/node_modules/pkg/index.js": "",
+	})
+
+	tarHelper, err := NewTar(src)
+	g.Expect(err).To(o.BeNil())
+	tarHelper.WithGitConfig(GitConfig{Home: home})
+	g.Expect(tarEntries(g, tarHelper)).To(o.Equal([]string{
+		".gitignore",
+		"api/node_modules/pkg/index.js",
+		"main.go",
+		"web/.gitignore",
+		"web/index.js",
+		"web/keep.tmp",
+		"web/src/dist/component.js",
+	}))
+
+	// an invalid user git configuration only skips the global patterns
+	writeFiles(g, home, map[string]string{".gitconfig": "[core\n"})
+	tarHelper.WithGitConfig(GitConfig{Home: home})
+	g.Expect(tarEntries(g, tarHelper)).To(o.ContainElement(".DS_Store"))
+
+	// without git ignore patterns everything but the ".git" directory is included
+	tarHelper.WithoutGitIgnore()
+	g.Expect(tarEntries(g, tarHelper)).To(o.HaveLen(12))
+}
+
+func Test_TarSymlinksModesAndDirectories(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	src := t.TempDir()
+	writeFiles(g, src, map[string]string{"bin/run.sh": "#!/bin/sh"})
+	g.Expect(os.Chmod(filepath.Join(src, "bin/run.sh"), 0o755)).To(o.Succeed())
+	g.Expect(os.Mkdir(filepath.Join(src, "empty"), 0o750)).To(o.Succeed())
+	g.Expect(os.Symlink("bin/run.sh", filepath.Join(src, "current"))).To(o.Succeed())
+	g.Expect(os.Symlink(filepath.Join(src, "bin/run.sh"), filepath.Join(src, "bin/absolute"))).To(o.Succeed())
+	g.Expect(os.Symlink("bin", filepath.Join(src, "lib"))).To(o.Succeed())
+
+	tarHelper, err := NewTar(src)
+	g.Expect(err).To(o.BeNil())
+	tarHelper.WithGitConfig(GitConfig{})
+	headers := tarHeaders(g, tarHelper)
+
+	g.Expect(headers).To(o.HaveKey("empty/"))
+	g.Expect(headers["empty/"].Typeflag).To(o.Equal(byte(tar.TypeDir)))
+	g.Expect(headers["empty/"].Mode & 0o777).To(o.Equal(int64(0o750)))
+	g.Expect(headers["bin/run.sh"].Mode & 0o777).To(o.Equal(int64(0o755)))
+
+	g.Expect(headers["current"].Typeflag).To(o.Equal(byte(tar.TypeSymlink)))
+	g.Expect(headers["current"].Linkname).To(o.Equal("bin/run.sh"))
+	g.Expect(headers["lib"].Typeflag).To(o.Equal(byte(tar.TypeSymlink)))
+	g.Expect(headers["lib"].Linkname).To(o.Equal("bin"))
+	// absolute links inside the source directory become relative
+	g.Expect(headers["bin/absolute"].Typeflag).To(o.Equal(byte(tar.TypeSymlink)))
+	g.Expect(headers["bin/absolute"].Linkname).To(o.Equal("run.sh"))
+
+	// dereferencing writes the target file contents, links to directories are not supported
+	g.Expect(os.Remove(filepath.Join(src, "lib"))).To(o.Succeed())
+	tarHelper.WithDereferenceSymlinks()
+	headers = tarHeaders(g, tarHelper)
+	g.Expect(headers["current"].Typeflag).To(o.Equal(byte(tar.TypeReg)))
+	g.Expect(headers["current"].Mode & 0o777).To(o.Equal(int64(0o755)))
+	g.Expect(tarHelper.Manifest().Entries()).To(o.HaveLen(3))
+
+	g.Expect(os.Symlink("bin", filepath.Join(src, "lib"))).To(o.Succeed())
+	err = tarHelper.Create(io.Discard)
+	g.Expect(err).ToNot(o.BeNil())
+	g.Expect(err.Error()).To(o.ContainSubstring("must point to a regular file"))
+}
+
+func Test_TarSymlinkEscapingSource(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	for _, linkname := range []string{"../outside", "/etc/passwd", "sub/../../outside"} {
+		src := t.TempDir()
+		writeFiles(g, src, map[string]string{"main.go": "package main"})
+		g.Expect(os.Symlink(linkname, filepath.Join(src, "link"))).To(o.Succeed())
+
+		tarHelper, err := NewTar(src)
+		g.Expect(err).To(o.BeNil())
+		tarHelper.WithGitConfig(GitConfig{})
+		err = tarHelper.Create(io.Discard)
+		g.Expect(err).ToNot(o.BeNil())
+		g.Expect(err.Error()).To(o.ContainSubstring("points outside of"))
+	}
+}
+
+func Test_TarReproducible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tarballs := [][]byte{}
+	for _, mtime := range []time.Time{time.Now(), time.Now().Add(-time.Hour)} {
+		src := t.TempDir()
+		writeFiles(g, src, map[string]string{"main.go": "package main", "pkg/lib.go": "package pkg"})
+		for _, name := range []string{"main.go", "pkg/lib.go", "pkg"} {
+			g.Expect(os.Chtimes(filepath.Join(src, name), mtime, mtime)).To(o.Succeed())
+		}
+
+		tarHelper, err := NewTar(src)
+		g.Expect(err).To(o.BeNil())
+		tarHelper.WithGitConfig(GitConfig{})
+		var buf bytes.Buffer
+		g.Expect(tarHelper.WithReproducible().Create(&buf)).To(o.Succeed())
+		tarballs = append(tarballs, buf.Bytes())
+
+		headers := tarHeaders(g, tarHelper)
+		g.Expect(headers["main.go"].ModTime.Unix()).To(o.Equal(int64(0)))
+		g.Expect(headers["main.go"].Uid).To(o.Equal(0))
+		g.Expect(headers["main.go"].Uname).To(o.BeEmpty())
+	}
+	g.Expect(tarballs[0]).To(o.Equal(tarballs[1]))
 }
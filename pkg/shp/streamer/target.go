@@ -1,5 +1,29 @@
 package streamer
 
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/shipwright-io/build/pkg/reconciler/buildrun/resources/sources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultBaseDir directory where the source waiter expects the data, the build source workspace.
+	DefaultBaseDir = "/workspace/source"
+	// sourceWorkspace name of the build source workspace, Tekton mounts it on "/workspace/source" by
+	// default, using a volume named with the "ws-" prefix.
+	sourceWorkspace = "source"
+	// workspaceVolumePrefix prefix of the volume names Tekton uses for workspaces.
+	workspaceVolumePrefix = "ws-"
+	// waiterName name of the source waiter executable, and of its container image.
+	waiterName = "waiter"
+	// waiterStartArg argument to start the waiter, waiting for the data upload.
+	waiterStartArg = "start"
+)
+
 // Target represents the target POD to receive streamed data.
 type Target struct {
 	Namespace string // kubernetes namespace
@@ -11,3 +35,134 @@ type Target struct {
 func (t *Target) IsEmpty() bool {
 	return t.Pod == "" || t.Namespace == ""
 }
+
+// containerStatus returns the status of the target container on the informed pod, nil when the
+// container has no status yet.
+func (t *Target) containerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == t.Container {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// IsRunning checks if the target container is running on the informed pod, thus ready to receive
+// the data. A error is returned when the container has terminated already.
+func (t *Target) IsRunning(pod *corev1.Pod) (bool, error) {
+	status := t.containerStatus(pod)
+	if status == nil {
+		return false, nil
+	}
+	if terminated := status.State.Terminated; terminated != nil {
+		return false, fmt.Errorf("container '%s' on build pod '%s' has terminated (%s) before receiving the data",
+			t.Container, t.Pod, terminated.Reason)
+	}
+	return status.State.Running != nil, nil
+}
+
+// imageName returns the last path component of the image name, without tag or digest.
+func imageName(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	name := path.Base(image)
+	return strings.SplitN(name, ":", 2)[0]
+}
+
+// isWaiter checks if the container runs the source waiter, either by its image name, or by the
+// waiter executable followed by the "start" argument on its command-line, which Tekton rewrites to
+// run with its entrypoint.
+func isWaiter(container *corev1.Container) bool {
+	if imageName(container.Image) == waiterName {
+		return true
+	}
+	waiter := false
+	for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+		switch {
+		case path.Base(arg) == waiterName:
+			waiter = true
+		case waiter && arg == waiterStartArg:
+			return true
+		}
+	}
+	return false
+}
+
+// sourceMount returns the volume mount of the source workspace, a Tekton workspace volume mounted
+// on a path named after the workspace, or the only Tekton workspace volume mounted by the container.
+func sourceMount(container *corev1.Container) *corev1.VolumeMount {
+	var workspaces []*corev1.VolumeMount
+	for i := range container.VolumeMounts {
+		mount := &container.VolumeMounts[i]
+		if !strings.HasPrefix(mount.Name, workspaceVolumePrefix) {
+			continue
+		}
+		if path.Base(path.Clean(mount.MountPath)) == sourceWorkspace {
+			return mount
+		}
+		workspaces = append(workspaces, mount)
+	}
+	if len(workspaces) == 1 {
+		return workspaces[0]
+	}
+	return nil
+}
+
+// baseDir returns the directory expecting the data, the mount path of the source workspace volume.
+// Without it, the default directory is used, and it must be on a volume mounted by the container,
+// otherwise the data would not be shared with the other build steps.
+func baseDir(container *corev1.Container) (string, error) {
+	if mount := sourceMount(container); mount != nil {
+		return path.Clean(mount.MountPath), nil
+	}
+	dir := DefaultBaseDir
+	if len(container.VolumeMounts) == 0 {
+		return dir, nil
+	}
+	for _, mount := range container.VolumeMounts {
+		mountPath := path.Clean(mount.MountPath)
+		if dir == mountPath || strings.HasPrefix(dir, strings.TrimSuffix(mountPath, "/")+"/") {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("source directory '%s' is not on a volume mounted by container '%s'", dir, container.Name)
+}
+
+// findWaiter returns the source waiter container on the informed pod, the container named by the
+// controller is preferred, otherwise it's searched by image and command-line.
+func findWaiter(pod *corev1.Pod) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == fmt.Sprintf("step-%s", sources.WaiterContainerName) {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	for i := range pod.Spec.Containers {
+		if isWaiter(&pod.Spec.Containers[i]) {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// NewTargetFromPod discovers the source waiter container on the build pod, and the directory it
+// expects the data on.
+func NewTargetFromPod(pod *corev1.Pod) (*Target, error) {
+	container := findWaiter(pod)
+	if container == nil {
+		names := []string{}
+		for _, c := range pod.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		return nil, fmt.Errorf("unable to find the source waiter container on build pod '%s', containers: %s",
+			pod.GetName(), strings.Join(names, ", "))
+	}
+	dir, err := baseDir(container)
+	if err != nil {
+		return nil, err
+	}
+	return &Target{
+		Namespace: pod.GetNamespace(),
+		Pod:       pod.GetName(),
+		Container: container.Name,
+		BaseDir:   dir,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package streamer
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NewTargetFromPod(t *testing.T) {
+	workspace := []corev1.VolumeMount{{Name: "tekton-internal-workspace", MountPath: "/workspace"}}
+	build := corev1.Container{Name: "step-build", Image: "builder:latest", VolumeMounts: workspace}
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		baseDir   string
+		err       string
+	}{{
+		name:      "named by the controller",
+		container: corev1.Container{Name: "step-source-local", Image: "registry/other:v1"},
+		baseDir:   DefaultBaseDir,
+	}, {
+		name: "waiter image",
+		container: corev1.Container{
+			Name:         "step-upload",
+			Image:        "ghcr.io/shipwright-io/build/waiter:v0.9.0@sha256:0000",
+			VolumeMounts: workspace,
+		},
+		baseDir: DefaultBaseDir,
+	}, {
+		name: "waiter command rewritten by tekton",
+		container: corev1.Container{
+			Name:         "step-upload",
+			Image:        "registry/custom:latest",
+			Command:      []string{"/tekton/bin/entrypoint"},
+			Args:         []string{"-entrypoint", "/ko-app/waiter", "--", "start", "--timeout=1h"},
+			VolumeMounts: workspace,
+		},
+		baseDir: DefaultBaseDir,
+	}, {
+		name: "source workspace mounted elsewhere",
+		container: corev1.Container{
+			Name: "step-source-local",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "tekton-internal-workspace", MountPath: "/workspace"},
+				{Name: "ws-cache-k8p2x", MountPath: "/workspace/cache"},
+				{Name: "ws-x4bq9", MountPath: "/src/source/"},
+			},
+		},
+		baseDir: "/src/source",
+	}, {
+		name: "only workspace volume",
+		container: corev1.Container{
+			Name: "step-source-local",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "tekton-internal-workspace", MountPath: "/workspace"},
+				{Name: "ws-x4bq9", MountPath: "/data"},
+			},
+		},
+		baseDir: "/data",
+	}, {
+		name: "source directory outside of volume mounts",
+		container: corev1.Container{
+			Name:         "step-source-local",
+			VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+		},
+		err: "source directory '/workspace/source' is not on a volume mounted by container 'step-source-local'",
+	}, {
+		name:      "no waiter",
+		container: corev1.Container{Name: "step-git", Image: "git:latest", Args: []string{"start"}},
+		err:       "unable to find the source waiter container on build pod 'pod', containers: step-build, step-git",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{build, tt.container}},
+			}
+			target, err := NewTargetFromPod(pod)
+			if tt.err != "" {
+				g.Expect(err).To(o.MatchError(tt.err))
+				return
+			}
+			g.Expect(err).To(o.BeNil())
+			g.Expect(*target).To(o.Equal(Target{
+				Namespace: "ns",
+				Pod:       "pod",
+				Container: tt.container.Name,
+				BaseDir:   tt.baseDir,
+			}))
+		})
+	}
+}
+
+func Test_TargetIsRunning(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	target := &Target{Namespace: "ns", Pod: "pod", Container: "step-source-local"}
+	pod := &corev1.Pod{}
+
+	running, err := target.IsRunning(pod)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(running).To(o.BeFalse())
+
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "step-source-local",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+	}}
+	running, err = target.IsRunning(pod)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(running).To(o.BeFalse())
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	running, err = target.IsRunning(pod)
+	g.Expect(err).To(o.BeNil())
+	g.Expect(running).To(o.BeTrue())
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{Reason: "Error"},
+	}
+	_, err = target.IsRunning(pod)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring("has terminated (Error) before receiving the data")))
+}
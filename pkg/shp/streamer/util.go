@@ -2,24 +2,70 @@ package streamer
 
 import (
 	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+// tarWriter writes the tar entries, the headers and the file contents.
+type tarWriter interface {
+	io.Writer
+	WriteHeader(header *tar.Header) error
+}
+
+// reproducibleWriter normalizes the tar headers before writing them, the modification time, owner
+// and group are reset, thus the same entries and contents always produce the same tar.
+type reproducibleWriter struct {
+	*tar.Writer
+}
+
+// WriteHeader normalizes the header informed, and writes it.
+func (r *reproducibleWriter) WriteHeader(header *tar.Header) error {
+	header.ModTime = time.Unix(0, 0)
+	header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+	return r.Writer.WriteHeader(header)
+}
+
 func trimPrefix(prefix, fpath string) string {
 	return strings.TrimPrefix(strings.Replace(fpath, prefix, "", -1), string(filepath.Separator))
 }
 
-func writeFileToTar(tw *tar.Writer, src, fpath string, stat fs.FileInfo) error {
+// writeDirToTar writes the informed directory entry on the tar, preserving its mode.
+func writeDirToTar(tw tarWriter, src, fpath string, stat fs.FileInfo) error {
+	header, err := tar.FileInfoHeader(stat, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(trimPrefix(src, fpath)) + "/"
+	return tw.WriteHeader(header)
+}
+
+// writeSymlinkToTar writes the informed symbolic link entry on the tar, pointing to linkname.
+func writeSymlinkToTar(tw tarWriter, src, fpath, linkname string, stat fs.FileInfo) error {
+	header, err := tar.FileInfoHeader(stat, filepath.ToSlash(linkname))
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(trimPrefix(src, fpath))
+	return tw.WriteHeader(header)
+}
+
+// writeFileToTar writes the informed file on the tar, the entry is recorded on the manifest with
+// the SHA-256 of the contents written. The file mode, including executable bits, is preserved.
+func writeFileToTar(tw tarWriter, src, fpath string, stat fs.FileInfo, manifest *Manifest) error {
 	header, err := tar.FileInfoHeader(stat, stat.Name())
 	if err != nil {
 		return err
 	}
 
-	header.Name = trimPrefix(src, fpath)
+	header.Name = filepath.ToSlash(trimPrefix(src, fpath))
 	if err := tw.WriteHeader(header); err != nil {
 		return err
 	}
@@ -28,8 +74,26 @@ func writeFileToTar(tw *tar.Writer, src, fpath string, stat fs.FileInfo) error {
 	if err != nil {
 		return err
 	}
-	if _, err := io.Copy(tw, f); err != nil {
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(tw, io.TeeReader(f, hash))
+	if err != nil {
 		return err
 	}
-	return f.Close()
+	manifest.Add(ManifestEntry{
+		Path:   header.Name,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+// isWithin checks if the informed path is the root directory or inside of it.
+func isWithin(root, fpath string) bool {
+	rel, err := filepath.Rel(root, fpath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
@@ -0,0 +1,143 @@
+package streamer
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher watches over the source directory of a Tar, using inotify or the equivalent file system
+// notification on the platform, and informs when the entries that would be uploaded have changed.
+// Only the directories uploaded are watched, and changes to ignored files are discarded, thus the
+// same ignore rules apply. Notifications are debounced, a change is informed once the directory
+// has been quiet for the debounce interval.
+type Watcher struct {
+	tar      *Tar              // tar helper, providing the entries and ignore rules
+	debounce time.Duration     // quiet interval before informing a change
+	fsw      *fsnotify.Watcher // file system notifications
+	dirs     map[string]bool   // directories being watched
+	snapshot string            // fingerprint of the entries
+	changes  chan struct{}     // informs the entries have changed
+	done     chan struct{}     // stops the event loop
+}
+
+// Changes exposes the channel informing the entries have changed, a single change is kept while
+// the consumer is busy.
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// fingerprint lists the entries, and returns a string representing their state.
+func (w *Watcher) fingerprint() (string, []Entry, error) {
+	entries, err := w.tar.List()
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "%s %s %d %s %d\n", e.Path, e.Mode, e.Size, e.Linkname, e.ModTime.UnixNano())
+	}
+	return sb.String(), entries, nil
+}
+
+// refresh lists the entries again, starting to watch the new directories, and returns true when
+// the entries have changed since the last refresh.
+func (w *Watcher) refresh() (bool, error) {
+	snapshot, entries, err := w.fingerprint()
+	if err != nil {
+		return false, err
+	}
+
+	dirs := map[string]bool{w.tar.Src(): true}
+	for _, e := range entries {
+		if e.Mode.IsDir() {
+			dirs[filepath.Join(w.tar.Src(), filepath.FromSlash(e.Path))] = true
+		}
+	}
+	for dir := range dirs {
+		if !w.dirs[dir] {
+			if err := w.fsw.Add(dir); err != nil {
+				return false, fmt.Errorf("unable to watch %q: %w", dir, err)
+			}
+		}
+	}
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			// removed directories are not watched anymore already
+			_ = w.fsw.Remove(dir)
+		}
+	}
+	w.dirs = dirs
+
+	changed := snapshot != w.snapshot
+	w.snapshot = snapshot
+	return changed, nil
+}
+
+// loop handles the file system notifications, debouncing them before refreshing the entries.
+func (w *Watcher) loop() {
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Error watching '%s': %v", w.tar.Src(), err)
+		case <-timer.C:
+			changed, err := w.refresh()
+			if err != nil {
+				log.Printf("Error inspecting '%s': %v", w.tar.Src(), err)
+				continue
+			}
+			if changed {
+				select {
+				case w.changes <- struct{}{}:
+				default:
+				}
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Close stops watching the source directory.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// NewWatcher starts watching the source directory of the informed Tar.
+func NewWatcher(t *Tar, debounce time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		tar:      t,
+		debounce: debounce,
+		fsw:      fsw,
+		dirs:     map[string]bool{},
+		changes:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if _, err = w.refresh(); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	go w.loop()
+	return w, nil
+}
@@ -0,0 +1,43 @@
+package streamer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	o "github.com/onsi/gomega"
+)
+
+func Test_Watcher(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	src := t.TempDir()
+	writeFiles(g, src, map[string]string{
+		"main.go":    "package main",
+		".gitignore": "*.log\nbuild/\n",
+	})
+	g.Expect(os.Mkdir(filepath.Join(src, "build"), 0o755)).To(o.Succeed())
+
+	tarHelper, err := NewTar(src)
+	g.Expect(err).To(o.BeNil())
+	tarHelper.WithGitConfig(GitConfig{})
+	w, err := NewWatcher(tarHelper, 50*time.Millisecond)
+	g.Expect(err).To(o.BeNil())
+	defer w.Close()
+
+	// changes to ignored entries are discarded
+	writeFiles(g, src, map[string]string{"app.log": "log", "build/app": "binary"})
+	g.Consistently(w.Changes(), 500*time.Millisecond).ShouldNot(o.Receive())
+
+	writeFiles(g, src, map[string]string{"main.go": "package main // changed"})
+	g.Eventually(w.Changes(), 2*time.Second).Should(o.Receive())
+
+	// new directories are watched as well
+	writeFiles(g, src, map[string]string{"pkg/lib.go": "package pkg"})
+	g.Eventually(w.Changes(), 2*time.Second).Should(o.Receive())
+	writeFiles(g, src, map[string]string{"pkg/lib.go": "package pkg // changed"})
+	g.Eventually(w.Changes(), 2*time.Second).Should(o.Receive())
+}
@@ -0,0 +1,324 @@
+package tail
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+// Format represents the log output format name.
+type Format string
+
+const (
+	// FormatText plain text format, each line prefixed by the step name.
+	FormatText Format = "text"
+	// FormatJSON each line is written as a JSON object (JSON lines).
+	FormatJSON Format = "json"
+	// FormatGitHub wraps each step in GitHub Actions log groups, failures become error annotations.
+	FormatGitHub Format = "github"
+	// FormatGitLab wraps each step in GitLab CI collapsible sections.
+	FormatGitLab Format = "gitlab"
+)
+
+// Formats lists all supported log output formats.
+var Formats = []Format{FormatText, FormatJSON, FormatGitHub, FormatGitLab}
+
+// ColorMode represents when to colorize the log output.
+type ColorMode string
+
+const (
+	// ColorAuto colorize only when writing to a terminal and NO_COLOR is not set.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always colorize the output.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colorize the output.
+	ColorNever ColorMode = "never"
+)
+
+// ColorModes lists all supported color modes.
+var ColorModes = []ColorMode{ColorAuto, ColorAlways, ColorNever}
+
+// ColorEnabled checks whether the output written on the informed writer should be colorized.
+func ColorEnabled(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return term.AllowsColorOutput(w)
+	}
+}
+
+// Step identifies the origin of a log stream, a container running a given build step.
+type Step struct {
+	BuildRun  string `json:"buildrun,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Name      string `json:"step,omitempty"`
+}
+
+// Entry represents a single log line, or message, and its origin.
+type Entry struct {
+	Step
+	Timestamp time.Time `json:"timestamp"`
+	Line      string    `json:"line"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Formatter renders log lines and messages on the informed writer.
+type Formatter interface {
+	// StepStarted is called before the first line of a step is written.
+	StepStarted(w io.Writer, step *Step)
+	// Line renders a single log line.
+	Line(w io.Writer, entry *Entry)
+	// StepFinished is called when the step log stream ends, failure is only informed when the
+	// step container has terminated with error.
+	StepFinished(w io.Writer, step *Step, failure string)
+	// Message renders a informational message not bound to a specific step.
+	Message(w io.Writer, buildRun string, msg string)
+}
+
+// groupedFormatter is implemented by the formatters rendering the lines of each step as a group,
+// the lines of concurrent steps can't be interleaved.
+type groupedFormatter interface {
+	Formatter
+	grouped()
+}
+
+// NewFormatter instantiate the Formatter for the informed format.
+func NewFormatter(format Format) (Formatter, error) {
+	switch format {
+	case "", FormatText:
+		return &TextFormatter{}, nil
+	case FormatJSON:
+		return &jsonFormatter{}, nil
+	case FormatGitHub:
+		return &githubFormatter{}, nil
+	case FormatGitLab:
+		return &gitlabFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("'%s' is an invalid log format", format)
+	}
+}
+
+// TextFormatter the default format, "[step] line", optionally colorized, aligned and showing the
+// time elapsed since the informed start.
+type TextFormatter struct {
+	lock       sync.Mutex
+	color      bool      // colorize step prefixes
+	timestamps bool      // show time elapsed since start
+	start      time.Time // reference for elapsed time
+	width      int       // step prefix width
+	qualified  bool      // prefix steps with the BuildRun name
+	brWidth    int       // BuildRun name prefix width
+}
+
+// stepColors ANSI color codes employed on step prefixes.
+var stepColors = []int{32, 33, 34, 35, 36, 92, 93, 94, 95, 96}
+
+// SetColor enables or disables colorized step prefixes.
+func (t *TextFormatter) SetColor(enabled bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.color = enabled
+}
+
+// SetTimestamps enables showing the time elapsed since start on each line.
+func (t *TextFormatter) SetTimestamps(start time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.timestamps = true
+	t.start = start
+}
+
+// AlignContainers pads step prefixes to the width of the longest step name, based on the informed
+// container names.
+func (t *TextFormatter) AlignContainers(containers []string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, container := range containers {
+		if width := len(stepName(container)); width > t.width {
+			t.width = width
+		}
+	}
+}
+
+// AlignBuildRuns prefixes each step with the BuildRun name, padded to the width of the longest of
+// the informed BuildRun names. Employed when the logs of several BuildRuns share the output.
+func (t *TextFormatter) AlignBuildRuns(buildRuns []string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.qualified = true
+	for _, buildRun := range buildRuns {
+		if width := len(buildRun); width > t.brWidth {
+			t.brWidth = width
+		}
+	}
+}
+
+// stepColor returns a stable color for the informed step name.
+func stepColor(step string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(step))
+	return stepColors[h.Sum32()%uint32(len(stepColors))]
+}
+
+// elapsed formats the duration as "mm:ss", or "h:mm:ss" when longer than one hour.
+func elapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// StepStarted no-op, steps are identified by the line prefix.
+func (*TextFormatter) StepStarted(io.Writer, *Step) {}
+
+// Line writes the line prefixed by the step name.
+func (t *TextFormatter) Line(w io.Writer, e *Entry) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	label, padding := e.Name, t.width-len(e.Name)
+	if t.qualified {
+		label = fmt.Sprintf("%s/%s", e.BuildRun, e.Name)
+		padding += t.brWidth - len(e.BuildRun)
+	}
+	prefix := fmt.Sprintf("[%s]", label)
+	if padding > 0 {
+		prefix += strings.Repeat(" ", padding)
+	}
+	if t.color {
+		prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", stepColor(label), prefix)
+	}
+	if t.timestamps {
+		prefix = fmt.Sprintf("%s %s", elapsed(e.Timestamp.Sub(t.start)), prefix)
+	}
+	fmt.Fprintf(w, "%s %s\n", prefix, e.Line)
+}
+
+// StepFinished no-op, failures are reported by the caller.
+func (*TextFormatter) StepFinished(io.Writer, *Step, string) {}
+
+// Message writes the message as-is.
+func (*TextFormatter) Message(w io.Writer, _ string, msg string) {
+	fmt.Fprint(w, msg)
+}
+
+// jsonFormatter writes one JSON object per line.
+type jsonFormatter struct{}
+
+func (j *jsonFormatter) write(w io.Writer, e *Entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+func (*jsonFormatter) StepStarted(io.Writer, *Step) {}
+
+func (j *jsonFormatter) Line(w io.Writer, e *Entry) {
+	j.write(w, e)
+}
+
+func (j *jsonFormatter) StepFinished(w io.Writer, step *Step, failure string) {
+	if failure == "" {
+		return
+	}
+	j.write(w, &Entry{Step: *step, Timestamp: time.Now(), Error: failure})
+}
+
+func (j *jsonFormatter) Message(w io.Writer, buildRun string, msg string) {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return
+	}
+	j.write(w, &Entry{Step: Step{BuildRun: buildRun}, Timestamp: time.Now(), Line: msg})
+}
+
+// githubFormatter employs GitHub Actions workflow commands to group the log lines of each step.
+type githubFormatter struct{}
+
+// githubEscape escapes the data of a workflow command, when property is set the property value
+// escaping rules are employed.
+func githubEscape(s string, property bool) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	if property {
+		s = strings.ReplaceAll(s, ":", "%3A")
+		s = strings.ReplaceAll(s, ",", "%2C")
+	}
+	return s
+}
+
+func (*githubFormatter) grouped() {}
+
+func (*githubFormatter) StepStarted(w io.Writer, step *Step) {
+	fmt.Fprintf(w, "::group::%s\n", githubEscape(step.Name, false))
+}
+
+func (*githubFormatter) Line(w io.Writer, e *Entry) {
+	fmt.Fprintln(w, e.Line)
+}
+
+func (*githubFormatter) StepFinished(w io.Writer, step *Step, failure string) {
+	fmt.Fprintln(w, "::endgroup::")
+	if failure != "" {
+		fmt.Fprintf(w, "::error title=%s::%s\n",
+			githubEscape(fmt.Sprintf("Step %s failed", step.Name), true),
+			githubEscape(failure, false),
+		)
+	}
+}
+
+func (*githubFormatter) Message(w io.Writer, _ string, msg string) {
+	fmt.Fprint(w, msg)
+}
+
+// gitlabFormatter employs GitLab CI collapsible sections to group the log lines of each step.
+type gitlabFormatter struct{}
+
+// gitlabSectionRE matches characters not allowed on GitLab section names.
+var gitlabSectionRE = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// gitlabSection returns the section name for the informed step.
+func gitlabSection(step *Step) string {
+	return gitlabSectionRE.ReplaceAllString(fmt.Sprintf("step_%s", step.Name), "_")
+}
+
+func (*gitlabFormatter) grouped() {}
+
+func (*gitlabFormatter) StepStarted(w io.Writer, step *Step) {
+	fmt.Fprintf(w, "\x1b[0Ksection_start:%d:%s\r\x1b[0K%s\n",
+		time.Now().Unix(), gitlabSection(step), step.Name)
+}
+
+func (*gitlabFormatter) Line(w io.Writer, e *Entry) {
+	fmt.Fprintln(w, e.Line)
+}
+
+func (*gitlabFormatter) StepFinished(w io.Writer, step *Step, failure string) {
+	fmt.Fprintf(w, "\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), gitlabSection(step))
+	if failure != "" {
+		fmt.Fprintf(w, "\x1b[31;1mERROR: %s\x1b[0m\n", failure)
+	}
+}
+
+func (*gitlabFormatter) Message(w io.Writer, _ string, msg string) {
+	fmt.Fprint(w, msg)
+}
@@ -0,0 +1,144 @@
+package tail
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Formatters(t *testing.T) {
+	step := &Step{BuildRun: "buildrun", Pod: "pod", Container: "step-build", Name: "build"}
+	entry := &Entry{Step: *step, Timestamp: time.Unix(0, 0).UTC(), Line: "line"}
+
+	t.Run("text", func(t *testing.T) {
+		g := NewWithT(t)
+		f, err := NewFormatter(FormatText)
+		g.Expect(err).To(BeNil())
+
+		var buf bytes.Buffer
+		f.StepStarted(&buf, step)
+		f.Line(&buf, entry)
+		f.StepFinished(&buf, step, "failure")
+		g.Expect(buf.String()).To(Equal("[build] line\n"))
+	})
+
+	t.Run("text-buildrun-prefix", func(t *testing.T) {
+		g := NewWithT(t)
+		f := &TextFormatter{}
+		f.AlignContainers([]string{"step-build", "step-push"})
+		f.AlignBuildRuns([]string{"br", "br-longer"})
+
+		var buf bytes.Buffer
+		f.Line(&buf, &Entry{Step: Step{BuildRun: "br", Name: "build"}, Line: "line"})
+		g.Expect(buf.String()).To(Equal("[br/build]        line\n"))
+	})
+
+	t.Run("text-aligned-colored-timestamps", func(t *testing.T) {
+		g := NewWithT(t)
+		f := &TextFormatter{}
+		f.AlignContainers([]string{"step-build", "step-source-default"})
+		f.SetColor(true)
+		f.SetTimestamps(time.Unix(0, 0).UTC().Add(-65 * time.Second))
+
+		var buf bytes.Buffer
+		f.Line(&buf, entry)
+		g.Expect(buf.String()).To(Equal(
+			fmt.Sprintf("01:05 \x1b[%dm[build]         \x1b[0m line\n", stepColor("build")),
+		))
+		g.Expect(stepColor("build")).To(Equal(stepColor("build")))
+	})
+
+	t.Run("color-mode", func(t *testing.T) {
+		g := NewWithT(t)
+		var buf bytes.Buffer
+		g.Expect(ColorEnabled(ColorAlways, &buf)).To(BeTrue())
+		g.Expect(ColorEnabled(ColorNever, &buf)).To(BeFalse())
+		// a buffer is not a terminal
+		g.Expect(ColorEnabled(ColorAuto, &buf)).To(BeFalse())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		g := NewWithT(t)
+		f, err := NewFormatter(FormatJSON)
+		g.Expect(err).To(BeNil())
+
+		var buf bytes.Buffer
+		f.Line(&buf, entry)
+		f.StepFinished(&buf, step, "failure")
+		f.Message(&buf, "buildrun", "message\n")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		g.Expect(len(lines)).To(Equal(3))
+
+		var decoded map[string]interface{}
+		g.Expect(json.Unmarshal([]byte(lines[0]), &decoded)).To(Succeed())
+		g.Expect(decoded).To(HaveKeyWithValue("buildrun", "buildrun"))
+		g.Expect(decoded).To(HaveKeyWithValue("pod", "pod"))
+		g.Expect(decoded).To(HaveKeyWithValue("container", "step-build"))
+		g.Expect(decoded).To(HaveKeyWithValue("step", "build"))
+		g.Expect(decoded).To(HaveKeyWithValue("timestamp", "1970-01-01T00:00:00Z"))
+		g.Expect(decoded).To(HaveKeyWithValue("line", "line"))
+
+		g.Expect(lines[1]).To(ContainSubstring(`"error":"failure"`))
+		g.Expect(lines[2]).To(ContainSubstring(`"line":"message"`))
+	})
+
+	t.Run("github", func(t *testing.T) {
+		g := NewWithT(t)
+		f, err := NewFormatter(FormatGitHub)
+		g.Expect(err).To(BeNil())
+
+		var buf bytes.Buffer
+		f.StepStarted(&buf, step)
+		f.Line(&buf, entry)
+		f.StepFinished(&buf, step, "exit code 1\nmore")
+		g.Expect(buf.String()).To(Equal(
+			"::group::build\nline\n::endgroup::\n::error title=Step build failed::exit code 1%0Amore\n",
+		))
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		g := NewWithT(t)
+		f, err := NewFormatter(FormatGitLab)
+		g.Expect(err).To(BeNil())
+
+		var buf bytes.Buffer
+		f.StepStarted(&buf, step)
+		f.Line(&buf, entry)
+		f.StepFinished(&buf, step, "failure")
+		out := buf.String()
+		g.Expect(out).To(MatchRegexp(`section_start:\d+:step_build\r\x1b\[0Kbuild\n`))
+		g.Expect(out).To(ContainSubstring("line\n"))
+		g.Expect(out).To(MatchRegexp(`section_end:\d+:step_build\r`))
+		g.Expect(out).To(ContainSubstring("ERROR: failure"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := NewFormatter("xml")
+		g.Expect(err).NotTo(BeNil())
+	})
+}
+
+func Test_elapsed(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(elapsed(-time.Second)).To(Equal("00:00"))
+	g.Expect(elapsed(61 * time.Second)).To(Equal("01:01"))
+	g.Expect(elapsed(time.Hour + 2*time.Minute + 3*time.Second)).To(Equal("1:02:03"))
+}
+
+func Test_splitTimestamp(t *testing.T) {
+	g := NewWithT(t)
+
+	ts, line := splitTimestamp("2022-04-01T10:00:00.123456789Z some log line")
+	g.Expect(ts.Equal(time.Date(2022, 4, 1, 10, 0, 0, 123456789, time.UTC))).To(BeTrue())
+	g.Expect(line).To(Equal("some log line"))
+
+	_, line = splitTimestamp("fake logs")
+	g.Expect(line).To(Equal("fake logs"))
+}
@@ -0,0 +1,83 @@
+package tail
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Output is shared by everything writing on the same writer, serializing the writes and, for
+// grouped log formats, holding the output of a step while the group of another step is open.
+type Output struct {
+	lock   sync.Mutex
+	group  *Step           // step owning the output, its group is open
+	reopen *Step           // step whose group was closed by a message, reopened on its next write
+	held   []*bytes.Buffer // groups of finished steps, written when the open group is finished
+}
+
+// Lock locks the output, implementing sync.Locker.
+func (o *Output) Lock() {
+	o.lock.Lock()
+}
+
+// Unlock unlocks the output, implementing sync.Locker.
+func (o *Output) Unlock() {
+	o.lock.Unlock()
+}
+
+// writer returns where the output of the step goes, the step takes over the output when no other
+// group is open, otherwise its output is held. It also informs when the group of the step was
+// closed by a message, and must be started again. The caller must hold the lock.
+func (o *Output) writer(w io.Writer, step *Step, pending *bytes.Buffer) (io.Writer, bool) {
+	reopen := o.reopen == step
+	if reopen {
+		o.reopen = nil
+	}
+	if o.group == nil {
+		o.group = step
+	}
+	if o.group != step {
+		return pending, reopen
+	}
+	if pending.Len() > 0 {
+		_, _ = pending.WriteTo(w)
+	}
+	return w, reopen
+}
+
+// CloseGroup finishes the group open, if any, so the message written next is not hidden inside of
+// it, the groups held meanwhile are written as well. The step owning the group starts it again on
+// its next write. The caller must hold the lock.
+func (o *Output) CloseGroup(w io.Writer, formatter Formatter) {
+	if o.group == nil {
+		return
+	}
+	formatter.StepFinished(w, o.group, "")
+	o.reopen = o.group
+	o.group = nil
+	for _, held := range o.held {
+		_, _ = held.WriteTo(w)
+	}
+	o.held = nil
+}
+
+// finish ends the group of the step, when it owns the output the groups held meanwhile are
+// written, otherwise its own output is held. The caller must hold the lock.
+func (o *Output) finish(w io.Writer, step *Step, pending *bytes.Buffer) {
+	if o.group != step {
+		held := &bytes.Buffer{}
+		_, _ = pending.WriteTo(held)
+		o.held = append(o.held, held)
+		return
+	}
+	o.group = nil
+	for _, held := range o.held {
+		_, _ = held.WriteTo(w)
+	}
+	o.held = nil
+}
+
+// NewOutput instantiate a Output.
+func NewOutput() *Output {
+	return &Output{}
+}
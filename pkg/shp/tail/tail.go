@@ -2,15 +2,21 @@ package tail
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/shipwright-io/cli/pkg/shp/archive"
 )
 
 // Tail represents a "tail" command streaming log outputs to stdout interface, and errors are written
@@ -22,6 +28,11 @@ type Tail struct {
 	stopLock  sync.Mutex
 	stopped   bool
 
+	buildRun  string             // buildrun name, informed on each log entry
+	formatter Formatter          // renders log lines
+	output    *Output            // serializes formatter calls, concurrent streams share stdout
+	archives  []*archive.Archive // stores a copy of the raw container logs
+
 	stdout io.Writer
 	stderr io.Writer
 }
@@ -36,13 +47,174 @@ func (t *Tail) SetStderr(w io.Writer) {
 	t.stderr = w
 }
 
+// SetOutput shares the output with other writers of the same stdout, serializing their writes.
+func (t *Tail) SetOutput(o *Output) {
+	t.output = o
+}
+
+// SetFormatter set an alternative log formatter.
+func (t *Tail) SetFormatter(f Formatter) {
+	t.formatter = f
+}
+
+// WithArchive adds a archive to store a copy of the container logs.
+func (t *Tail) WithArchive(a *archive.Archive) *Tail {
+	t.archives = append(t.archives, a)
+	return t
+}
+
+// SetBuildRun set the BuildRun name the logs belong to.
+func (t *Tail) SetBuildRun(name string) {
+	t.buildRun = name
+}
+
+// isStopped checks if the stop channel has been closed.
+func (t *Tail) isStopped() bool {
+	t.stopLock.Lock()
+	defer t.stopLock.Unlock()
+	return t.stopped
+}
+
+// splitTimestamp splits the RFC3339 timestamp prefix added by Kubernetes on each log line, when
+// the line does not contain a valid timestamp the current time is used instead.
+func splitTimestamp(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 2)
+	ts, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Now(), line
+	}
+	if len(parts) == 1 {
+		return ts, ""
+	}
+	return ts, parts[1]
+}
+
+// stepFailure inspects the informed container status in order to describe a failure, when the
+// poll flag is set it gives the container a few seconds to reach the terminated state.
+func (t *Tail) stepFailure(ns, podName, container string, poll bool) string {
+	failure := ""
+	condition := func() (bool, error) {
+		pod, err := t.clientset.CoreV1().Pods(ns).Get(t.ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, s := range statuses {
+			if s.Name != container || s.State.Terminated == nil {
+				continue
+			}
+			if terminated := s.State.Terminated; terminated.ExitCode != 0 {
+				failure = fmt.Sprintf("step %q failed with exit code %d", stepName(container), terminated.ExitCode)
+				if terminated.Message != "" {
+					failure = fmt.Sprintf("%s: %s", failure, terminated.Message)
+				}
+			}
+			return true, nil
+		}
+		return false, nil
+	}
+	if !poll {
+		_, _ = condition()
+		return failure
+	}
+	_ = wait.PollImmediate(500*time.Millisecond, 5*time.Second, condition)
+	return failure
+}
+
+// stepName strips the Tekton "step-" prefix from container names.
+func stepName(container string) string {
+	return strings.TrimPrefix(container, "step-")
+}
+
+// openArchives creates the log files for the informed step on all archives, errors are reported
+// on stderr and the respective archive is skipped.
+func (t *Tail) openArchives(ns string, step *Step) []io.WriteCloser {
+	writers := []io.WriteCloser{}
+	for _, a := range t.archives {
+		w, err := a.Create(ns, step.BuildRun, step.Pod, step.Container)
+		if err != nil {
+			fmt.Fprintf(t.stderr, "unable to archive logs of container %q: %s\n", step.Container, err)
+			continue
+		}
+		writers = append(writers, w)
+	}
+	return writers
+}
+
+// render reads the log stream line by line, and writes it using the formatter. The step is only
+// marked as started when the first line arrives, steps without output don't open a group. For
+// grouped formats, the output of the step is held while the group of another step is open, so
+// concurrent streams don't mix groups.
+func (t *Tail) render(w io.Writer, r io.Reader, ns string, step *Step, failureFn func() string) {
+	archives := t.openArchives(ns, step)
+	defer func() {
+		for _, a := range archives {
+			a.Close()
+		}
+	}()
+
+	_, grouped := t.formatter.(groupedFormatter)
+	var pending bytes.Buffer
+	writer := func() (io.Writer, bool) {
+		if !grouped {
+			return w, false
+		}
+		return t.output.writer(w, step, &pending)
+	}
+
+	started := false
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		for _, a := range archives {
+			fmt.Fprintln(a, sc.Text())
+		}
+		ts, line := splitTimestamp(sc.Text())
+		t.output.Lock()
+		out, reopen := writer()
+		if !started || reopen {
+			t.formatter.StepStarted(out, step)
+			started = true
+		}
+		t.formatter.Line(out, &Entry{Step: *step, Timestamp: ts, Line: line})
+		t.output.Unlock()
+	}
+
+	failure := ""
+	if failureFn != nil {
+		failure = failureFn()
+	}
+	if !started && failure == "" {
+		return
+	}
+	t.output.Lock()
+	defer t.output.Unlock()
+	out, reopen := writer()
+	// a group closed by a message is only started again to report the failure
+	if !started || (reopen && failure != "") {
+		t.formatter.StepStarted(out, step)
+		reopen = false
+	}
+	if !reopen {
+		t.formatter.StepFinished(out, step, failure)
+	}
+	if grouped {
+		t.output.finish(w, step, &pending)
+	}
+}
+
+// step returns the Step representing the informed container.
+func (t *Tail) step(podName, container string) *Step {
+	return &Step{BuildRun: t.buildRun, Pod: podName, Container: container, Name: stepName(container)}
+}
+
 // Start start streaming logs for informed target.
 func (t *Tail) Start(ns, podName, container string) {
 	go func() {
 		podClient := t.clientset.CoreV1().Pods(ns)
 		stream, err := podClient.GetLogs(podName, &corev1.PodLogOptions{
-			Follow:    true,
-			Container: container,
+			Follow:     true,
+			Container:  container,
+			Timestamps: true,
 		}).Stream(t.ctx)
 		if err != nil {
 			fmt.Fprintln(t.stderr, err)
@@ -55,11 +227,13 @@ func (t *Tail) Start(ns, podName, container string) {
 			stream.Close()
 		}()
 
-		containerName := strings.TrimPrefix(container, "step-")
-		sc := bufio.NewScanner(stream)
-		for sc.Scan() {
-			fmt.Fprintf(t.stdout, "[%s] %s\n", containerName, sc.Text())
-		}
+		t.render(t.stdout, stream, ns, t.step(podName, container), func() string {
+			// when the stream is interrupted the container state is not relevant anymore
+			if t.isStopped() || t.ctx.Err() != nil {
+				return ""
+			}
+			return t.stepFailure(ns, podName, container, true)
+		})
 	}()
 	go func() {
 		<-t.ctx.Done()
@@ -67,6 +241,84 @@ func (t *Tail) Start(ns, podName, container string) {
 	}()
 }
 
+// dump writes the complete log of the informed container on the writer.
+func (t *Tail) dump(w io.Writer, ns, podName, container string) error {
+	stream, err := t.clientset.CoreV1().Pods(ns).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+	}).Stream(t.ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	t.render(w, stream, ns, t.step(podName, container), func() string {
+		return t.stepFailure(ns, podName, container, false)
+	})
+	return nil
+}
+
+// Dump writes the complete log of the informed container, blocking until all lines are written.
+func (t *Tail) Dump(ns, podName, container string) error {
+	return t.dump(t.stdout, ns, podName, container)
+}
+
+// Archive stores the complete log of the informed container on the archives only.
+func (t *Tail) Archive(ns, podName, container string) error {
+	return t.dump(io.Discard, ns, podName, container)
+}
+
+// Fetch retrieves the complete log of the informed container, storing it on the archives, and
+// returns it without timestamps.
+func (t *Tail) Fetch(ns, podName, container string) (string, error) {
+	stream, err := t.clientset.CoreV1().Pods(ns).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+	}).Stream(t.ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	archives := t.openArchives(ns, t.step(podName, container))
+	defer func() {
+		for _, a := range archives {
+			a.Close()
+		}
+	}()
+
+	var b strings.Builder
+	sc := bufio.NewScanner(stream)
+	for sc.Scan() {
+		for _, a := range archives {
+			fmt.Fprintln(a, sc.Text())
+		}
+		_, line := splitTimestamp(sc.Text())
+		fmt.Fprintln(&b, line)
+	}
+	return b.String(), sc.Err()
+}
+
+// Replay writes the log of a archived container, as if it was obtained from the cluster.
+func (t *Tail) Replay(ns string, c archive.Container) error {
+	f, err := os.Open(c.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	t.render(t.stdout, f, ns, t.step(c.Pod, c.Name), nil)
+	return nil
+}
+
+// Message writes a informational message using the formatter.
+func (t *Tail) Message(msg string) {
+	t.output.Lock()
+	defer t.output.Unlock()
+	t.output.CloseGroup(t.stdout, t.formatter)
+	t.formatter.Message(t.stdout, t.buildRun, msg)
+}
+
 // Stop closes stop channel to stop log streaming.
 func (t *Tail) Stop() {
 	// employ sync because of observed 'panic: close of closed channel' when running build run log following
@@ -86,6 +338,8 @@ func NewTail(ctx context.Context, clientset kubernetes.Interface) *Tail {
 		clientset: clientset,
 		stopCh:    make(chan bool, 1),
 		stopLock:  sync.Mutex{},
+		formatter: &TextFormatter{},
+		output:    NewOutput(),
 		stdout:    os.Stdout,
 		stderr:    os.Stderr,
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -59,3 +60,75 @@ func Test_Tail(t *testing.T) {
 	g.Expect(err).To(BeNil())
 	g.Expect(stderrNumBytes).To(Equal(int64(0)))
 }
+
+func Test_TailGroupedSteps(t *testing.T) {
+	g := NewWithT(t)
+
+	var out bytes.Buffer
+	logTail := NewTail(context.TODO(), fake.NewSimpleClientset())
+	logTail.SetFormatter(&githubFormatter{})
+	output := func() string {
+		logTail.output.Lock()
+		defer logTail.output.Unlock()
+		return out.String()
+	}
+
+	// step "a" opens its group first, step "b" runs and finishes meanwhile
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		logTail.render(&out, r, "ns", &Step{Name: "a"}, nil)
+		close(done)
+	}()
+	_, err := io.WriteString(w, "a1\n")
+	g.Expect(err).To(BeNil())
+	g.Eventually(output).Should(ContainSubstring("a1"))
+
+	logTail.render(&out, strings.NewReader("b1\nb2\n"), "ns", &Step{Name: "b"}, nil)
+	g.Expect(output()).To(Equal("::group::a\na1\n"))
+
+	_, err = io.WriteString(w, "a2\n")
+	g.Expect(err).To(BeNil())
+	w.Close()
+	<-done
+	g.Expect(output()).To(Equal("::group::a\na1\na2\n::endgroup::\n::group::b\nb1\nb2\n::endgroup::\n"))
+}
+
+func Test_TailGroupClosedByMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	var out bytes.Buffer
+	logTail := NewTail(context.TODO(), fake.NewSimpleClientset())
+	logTail.SetStdout(&out)
+	logTail.SetFormatter(&githubFormatter{})
+	output := func() string {
+		logTail.output.Lock()
+		defer logTail.output.Unlock()
+		return out.String()
+	}
+
+	// step "a" opens its group, step "b" is held meanwhile, the message closes the group of "a"
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		logTail.render(&out, r, "ns", &Step{Name: "a"}, nil)
+		close(done)
+	}()
+	_, err := io.WriteString(w, "a1\n")
+	g.Expect(err).To(BeNil())
+	g.Eventually(output).Should(ContainSubstring("a1"))
+	logTail.render(&out, strings.NewReader("b1\n"), "ns", &Step{Name: "b"}, nil)
+
+	logTail.Message("attempt 2\n")
+	g.Expect(output()).To(Equal("::group::a\na1\n::endgroup::\n::group::b\nb1\n::endgroup::\nattempt 2\n"))
+
+	// the group of "a" is started again on its next line
+	_, err = io.WriteString(w, "a2\n")
+	g.Expect(err).To(BeNil())
+	w.Close()
+	<-done
+	g.Expect(output()).To(Equal("" +
+		"::group::a\na1\n::endgroup::\n::group::b\nb1\n::endgroup::\nattempt 2\n" +
+		"::group::a\na2\n::endgroup::\n",
+	))
+}
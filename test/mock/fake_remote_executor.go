@@ -15,6 +15,28 @@ type FakeRemoteExecutor struct {
 	command []string     // extracted from query parameter ("command")
 	stdin   bytes.Buffer // standard input informed
 	err     error        // stubbed error
+	stderr  string       // stubbed standard error output
+	stdout  string       // stubbed standard output
+	limit   int64        // amount of stdin bytes read, all of it when zero
+}
+
+// WithStdinLimit sets the amount of bytes read from the standard input, mimicking a remote command
+// exiting before reading all of it.
+func (f *FakeRemoteExecutor) WithStdinLimit(limit int64) *FakeRemoteExecutor {
+	f.limit = limit
+	return f
+}
+
+// WithStdout sets the standard output written by Execute.
+func (f *FakeRemoteExecutor) WithStdout(stdout string) *FakeRemoteExecutor {
+	f.stdout = stdout
+	return f
+}
+
+// WithStderr sets the standard error output written by Execute.
+func (f *FakeRemoteExecutor) WithStderr(stderr string) *FakeRemoteExecutor {
+	f.stderr = stderr
+	return f
 }
 
 // Command returns the command informed to Execute.
@@ -44,10 +66,23 @@ func (f *FakeRemoteExecutor) Execute(
 	}
 
 	if stdin != nil {
+		if f.limit > 0 {
+			stdin = io.LimitReader(stdin, f.limit)
+		}
 		if _, err := io.Copy(&f.stdin, stdin); err != nil {
 			return err
 		}
 	}
+	if stdout != nil && f.stdout != "" {
+		if _, err := io.WriteString(stdout, f.stdout); err != nil {
+			return err
+		}
+	}
+	if stderr != nil && f.stderr != "" {
+		if _, err := io.WriteString(stderr, f.stderr); err != nil {
+			return err
+		}
+	}
 	return f.err
 }
 